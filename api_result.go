@@ -16,6 +16,14 @@ func (r *ApiResult) IsSuccess() bool {
 	return r.Code == 0
 }
 
+// Err 返回码非0时返回APIError，否则返回nil
+func (r *ApiResult) Err() error {
+	if r.IsSuccess() {
+		return nil
+	}
+	return &APIError{Code: r.Code, Message: r.Msg}
+}
+
 // GetData 获取响应数据
 func (r *ApiResult) GetData() json.RawMessage {
 	return r.Data
@@ -0,0 +1,144 @@
+package getui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// AudienceKind 受众类型
+type AudienceKind string
+
+const (
+	AudienceKindNone          AudienceKind = ""                // 未指定
+	AudienceKindCID           AudienceKind = "cid"             // CID列表
+	AudienceKindAlias         AudienceKind = "alias"           // 别名列表
+	AudienceKindTag           AudienceKind = "tag"             // 标签条件
+	AudienceKindFastCustomTag AudienceKind = "fast_custom_tag" // 快速标签
+	AudienceKindFileID        AudienceKind = "file_id"         // 文件ID
+	AudienceKindAll           AudienceKind = "all"             // 全部用户
+	AudienceKindMixed         AudienceKind = "mixed"           // 同时指定了多种受众，无效
+)
+
+// audienceAll 全部用户受众的JSON值
+const audienceAll = "all"
+
+// Audience 受众，每个受众只能指定一种类型，建议使用ByCIDs、ByAliases、ByTags、
+// ByFileID、All、FastCustomTag创建。全部用户序列化为"all"，其余序列化为对象
+type Audience struct {
+	CIDs          []string       `json:"cid,omitempty"`
+	Alias         []string       `json:"alias,omitempty"`
+	Tag           []TagCondition `json:"tag,omitempty"`
+	FastCustomTag string         `json:"fast_custom_tag,omitempty"`
+	FileID        string         `json:"file_id,omitempty"`
+	All           bool           `json:"-"`
+}
+
+// TagCondition 标签推送的筛选条件，建议通过Tag创建
+type TagCondition struct {
+	Key     string   `json:"key"`      // 条件类型：phone_type、region、custom_tag、portrait
+	Values  []string `json:"values"`   // 条件值
+	OptType string   `json:"opt_type"` // values之间的关系：or、and、not
+}
+
+// ByCIDs 按CID推送
+func ByCIDs(cids ...string) *Audience {
+	return &Audience{CIDs: cids}
+}
+
+// ByAliases 按别名推送
+func ByAliases(aliases ...string) *Audience {
+	return &Audience{Alias: aliases}
+}
+
+// ByTags 按标签条件推送，多个条件之间是与的关系
+func ByTags(conditions ...TagCondition) *Audience {
+	return &Audience{Tag: conditions}
+}
+
+// ByFileID 按上传的文件ID推送
+func ByFileID(fileID string) *Audience {
+	return &Audience{FileID: fileID}
+}
+
+// All 推送给全部用户
+func All() *Audience {
+	return &Audience{All: true}
+}
+
+// FastCustomTag 按快速标签推送
+func FastCustomTag(tag string) *Audience {
+	return &Audience{FastCustomTag: tag}
+}
+
+// Kind 获取受众类型，没有指定时返回AudienceKindNone，指定了多种时返回AudienceKindMixed
+func (a *Audience) Kind() AudienceKind {
+	if a == nil {
+		return AudienceKindNone
+	}
+
+	kind := AudienceKindNone
+	set := func(k AudienceKind, ok bool) {
+		if !ok {
+			return
+		}
+		if kind == AudienceKindNone {
+			kind = k
+		} else {
+			kind = AudienceKindMixed
+		}
+	}
+	set(AudienceKindCID, len(a.CIDs) > 0)
+	set(AudienceKindAlias, len(a.Alias) > 0)
+	set(AudienceKindTag, len(a.Tag) > 0)
+	set(AudienceKindFastCustomTag, a.FastCustomTag != "")
+	set(AudienceKindFileID, a.FileID != "")
+	set(AudienceKindAll, a.All)
+	return kind
+}
+
+// validateKind 校验受众类型是否为接口要求的类型
+func (a *Audience) validateKind(endpoint string, expected AudienceKind) error {
+	switch kind := a.Kind(); kind {
+	case AudienceKindNone:
+		return ErrEmptyAudience
+	case AudienceKindMixed:
+		return fmt.Errorf("%w: only one audience kind can be specified", ErrInvalidAudience)
+	case expected:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s requires %s audience, got %s", ErrInvalidAudience, endpoint, expected, kind)
+	}
+}
+
+// MarshalJSON 全部用户序列化为"all"，其余序列化为对象
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if a.All {
+		return json.Marshal(audienceAll)
+	}
+	type audience Audience
+	return json.Marshal(audience(a))
+}
+
+// UnmarshalJSON 解析"all"或受众对象
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s != audienceAll {
+			return fmt.Errorf("%w: unknown audience %q", ErrInvalidAudience, s)
+		}
+		*a = Audience{All: true}
+		return nil
+	}
+
+	type audience Audience
+	var v audience
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*a = Audience(v)
+	return nil
+}
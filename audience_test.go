@@ -0,0 +1,79 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestAudience_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		audience *Audience
+		expected string
+		kind     AudienceKind
+	}{
+		{"CID", ByCIDs("cid_1", "cid_2"), `{"cid":["cid_1","cid_2"]}`, AudienceKindCID},
+		{"别名", ByAliases("user_1"), `{"alias":["user_1"]}`, AudienceKindAlias},
+		{"标签", ByTags(TagCondition{Key: "phone_type", Values: []string{"android"}, OptType: "and"}),
+			`{"tag":[{"key":"phone_type","values":["android"],"opt_type":"and"}]}`, AudienceKindTag},
+		{"快速标签", FastCustomTag("vip"), `{"fast_custom_tag":"vip"}`, AudienceKindFastCustomTag},
+		{"文件", ByFileID("file_1"), `{"file_id":"file_1"}`, AudienceKindFileID},
+		{"全部用户", All(), `"all"`, AudienceKindAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.kind, tt.audience.Kind(), "受众类型")
+
+			data, err := json.Marshal(&PushDTO{Audience: tt.audience})
+			assertNoError(t, err, "序列化不应返回错误")
+			var raw map[string]json.RawMessage
+			json.Unmarshal(data, &raw)
+			assertEqual(t, tt.expected, string(raw["audience"]), "序列化结果")
+
+			var decoded PushDTO
+			assertNoError(t, json.Unmarshal(data, &decoded), "反序列化不应返回错误")
+			assertEqual(t, tt.kind, decoded.Audience.Kind(), "反序列化后受众类型应不变")
+		})
+	}
+
+	var audience Audience
+	assertTrue(t, errors.Is(json.Unmarshal([]byte(`"some"`), &audience), ErrInvalidAudience), "未知的字符串受众应返回错误")
+}
+
+func TestAudience_Kind(t *testing.T) {
+	var nilAudience *Audience
+	assertEqual(t, AudienceKindNone, nilAudience.Kind(), "nil受众没有类型")
+	assertEqual(t, AudienceKindNone, (&Audience{CIDs: []string{}}).Kind(), "空列表没有类型")
+	assertEqual(t, AudienceKindMixed, (&Audience{CIDs: []string{"cid_1"}, Alias: []string{"user_1"}}).Kind(), "多种受众应为mixed")
+}
+
+func TestPushAPI_AudienceKindMismatch(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"taskid":"RASA_1"}}`)
+	message := createTestPushMessage()
+
+	_, err := client.PushAPI.PushToSingleByCID(&PushDTO{Audience: ByAliases("user_1"), PushMessage: message})
+	assertTrue(t, errors.Is(err, ErrInvalidAudience), "CID单推使用别名受众应返回错误")
+
+	_, err = client.PushAPI.PushByTag(&PushDTO{Audience: FastCustomTag("vip"), PushMessage: message})
+	assertTrue(t, errors.Is(err, ErrInvalidAudience), "标签推送使用快速标签受众应返回错误")
+
+	_, err = client.PushAPI.PushAll(&PushDTO{Audience: ByCIDs("cid_1"), PushMessage: message})
+	assertTrue(t, errors.Is(err, ErrInvalidAudience), "群推不应覆盖指定的受众")
+
+	_, err = client.PushAPI.PushListByAlias(&AudienceDTO{Audience: &Audience{Alias: []string{"user_1"}, All: true}})
+	assertTrue(t, errors.Is(err, ErrInvalidAudience), "同时指定多种受众应返回错误")
+
+	_, err = client.PushAPI.PushToSingleByCID(&PushDTO{Audience: &Audience{}, PushMessage: message})
+	assertEqual(t, ErrEmptyAudience, err, "空受众应返回ErrEmptyAudience")
+
+	pushDTO := &PushDTO{PushMessage: message}
+	result, err := client.PushAPI.PushAll(pushDTO)
+	assertNoError(t, err, "群推未指定受众时应推送给全部用户")
+	assertEqual(t, "RASA_1", result.TaskID(), "应返回任务ID")
+	assertTrue(t, pushDTO.Audience.All, "应设置为全部用户")
+
+	_, err = client.PushAPI.CreateMsg(&PushDTO{PushMessage: message})
+	assertNoError(t, err, "创建消息不需要受众")
+}
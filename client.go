@@ -1,19 +1,26 @@
 package getui
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 )
 
 // Client 个推SDK客户端
 type Client struct {
-	config       *Config
-	httpClient   *http.Client
-	tokenManager *TokenManager
+	config        *Config
+	httpClient    *http.Client
+	tokenManager  *TokenManager
+	domainManager *DomainManager
+	healthMonitor *HealthMonitor
+
+	closeOnce sync.Once
+	closeErr  error
 
 	// API接口
 	PushAPI      *PushAPI
@@ -21,22 +28,46 @@ type Client struct {
 	StatisticAPI *StatisticAPI
 }
 
-// NewClient 创建新的客户端
+// NewClient 创建新的客户端，配置无效时panic，需要返回错误时使用NewClientWithOptions
 func NewClient(config *Config) *Client {
+	client, err := NewClientWithOptions(config)
+	if err != nil {
+		panic(fmt.Sprintf("invalid config: %v", err))
+	}
+	return client
+}
+
+// NewClientWithOptions 创建新的客户端，应用opts后校验配置，配置无效时返回ConfigErrors。
+// opts作用于config的副本，不会修改调用方的配置，也不会影响使用同一配置创建的其他客户端
+func NewClientWithOptions(config *Config, opts ...Option) (*Client, error) {
 	if config == nil {
 		config = NewDefaultConfig()
+	} else if len(opts) > 0 {
+		config = config.clone()
+	}
+	for _, opt := range opts {
+		opt(config)
 	}
 
 	// 验证配置
 	if err := config.Validate(); err != nil {
-		panic(fmt.Sprintf("invalid config: %v", err))
+		return nil, err
 	}
 
 	httpClient := config.GetHTTPClient()
 	client := &Client{
-		config:       config,
-		httpClient:   httpClient,
-		tokenManager: NewTokenManager(config, httpClient),
+		config:        config,
+		httpClient:    httpClient,
+		tokenManager:  NewTokenManager(config, httpClient),
+		domainManager: NewDomainManager(config, httpClient),
+	}
+	client.healthMonitor = NewHealthMonitor(config, client.tokenHealth, client.domainManager.CurrentDomain)
+	client.tokenManager.domainManager = client.domainManager
+	client.tokenManager.healthMonitor = client.healthMonitor
+	client.domainManager.Start()
+	client.healthMonitor.Start()
+	if config.TokenAutoRefresh {
+		client.tokenManager.StartAutoRefresh()
 	}
 
 	// 初始化API接口
@@ -44,26 +75,34 @@ func NewClient(config *Config) *Client {
 	client.UserAPI = &UserAPI{client: client}
 	client.StatisticAPI = &StatisticAPI{client: client}
 
-	return client
+	return client, nil
 }
 
 // GetToken 获取认证token
 func (c *Client) GetToken() (string, error) {
-	return c.tokenManager.GetToken()
+	return c.GetTokenContext(context.Background())
 }
 
-// DoRequest 执行HTTP请求
+// GetTokenContext 获取认证token（支持context）
+func (c *Client) GetTokenContext(ctx context.Context) (string, error) {
+	return c.tokenManager.GetTokenContext(ctx)
+}
+
+// DoRequest 执行HTTP请求并返回原始响应，不检查返回码，需要解析数据时使用Call
 func (c *Client) DoRequest(method, uri string, body interface{}) (*ApiResult, error) {
+	return c.DoRequestContext(context.Background(), method, uri, body)
+}
+
+// DoRequestContext 执行HTTP请求，ctx的取消和截止时间会传递到token获取和HTTP请求。
+// 服务端返回token无效时会清除缓存的token、重新鉴权并重放一次请求
+func (c *Client) DoRequestContext(ctx context.Context, method, uri string, body interface{}) (*ApiResult, error) {
 	// 获取token
-	token, err := c.GetToken()
+	token, err := c.GetTokenContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// 构建URL
-	url := fmt.Sprintf("%s/%s%s", c.config.Domain, c.config.AppID, uri)
-
-	// 准备请求体
+	// 准备请求体，重试时复用同一份请求体，保证request_id不变以便服务端去重
 	var reqBody []byte
 	if body != nil {
 		reqBody, err = json.Marshal(body)
@@ -72,35 +111,102 @@ func (c *Client) DoRequest(method, uri string, body interface{}) (*ApiResult, er
 		}
 	}
 
+	result, err := c.send(ctx, method, uri, token, reqBody)
+	if err != nil || !isTokenErrorCode(result.Code) {
+		return result, err
+	}
+
+	// token被服务端拒绝，重新鉴权后重放请求
+	c.tokenManager.InvalidateToken(token)
+	token, err = c.GetTokenContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = c.send(ctx, method, uri, token, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if isTokenErrorCode(result.Code) {
+		c.tokenManager.InvalidateToken(token)
+		return nil, newTokenError(result)
+	}
+
+	return result, nil
+}
+
+// send 按重试策略发送请求
+func (c *Client) send(ctx context.Context, method, uri, token string, reqBody []byte) (*ApiResult, error) {
+	policy := c.config.GetRetryPolicy()
+	var result *ApiResult
+	err := policy.retry(ctx, func() (bool, error) {
+		var retryable bool
+		var attemptErr error
+		result, retryable, attemptErr = c.doOnce(ctx, policy, method, uri, token, reqBody)
+		return retryable, attemptErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// doOnce 使用当前域名执行一次HTTP请求，返回结果以及是否可以重试
+func (c *Client) doOnce(ctx context.Context, policy *RetryPolicy, method, uri, token string, reqBody []byte) (*ApiResult, bool, error) {
+	// 构建URL
+	domain := c.domainManager.CurrentDomain()
+	url := fmt.Sprintf("%s/%s%s", domain, c.config.AppID, uri)
+
+	// 按URI设置本次请求的超时
+	reqCtx, cancel := withRequestTimeout(ctx, c.config, uri)
+	defer cancel()
+
 	// 创建请求
-	req, err := http.NewRequest(method, url, strings.NewReader(string(reqBody)))
+	req, err := http.NewRequestWithContext(reqCtx, method, url, bytes.NewReader(reqBody))
 	if err != nil {
-		return nil, &NetworkError{Message: "failed to create request", Cause: err}
+		return nil, false, &NetworkError{Message: "failed to create request", Cause: err}
 	}
 
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json;charset=utf-8")
 	req.Header.Set("token", token)
-
-	// 设置自定义超时
-	if customTimeout := c.config.GetCustomSocketTimeout(uri); customTimeout > 0 {
-		c.httpClient.Timeout = time.Duration(customTimeout) * time.Millisecond
-	}
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	// 执行请求
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, &NetworkError{Message: "failed to send request", Cause: err}
+		err = &NetworkError{Message: "failed to send request", Cause: requestError(reqCtx, err)}
+		if ctx.Err() == nil {
+			c.domainManager.ReportFailure(domain)
+			c.healthMonitor.Record(uri, time.Since(start), err)
+		}
+		return nil, IsRetryable(err), err
 	}
 	defer resp.Body.Close()
 
+	if isRetryableStatus(resp.StatusCode) {
+		c.domainManager.ReportFailure(domain)
+	} else {
+		c.domainManager.ReportSuccess(domain)
+	}
+
 	// 解析响应
-	var result ApiResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, &NetworkError{Message: "failed to decode response", Cause: err}
+	result, err := readResult(resp, c.config.MaxResponseBodySize, c.config.now())
+	if err != nil {
+		c.healthMonitor.Record(uri, time.Since(start), err)
+		return nil, IsRetryable(err), err
 	}
 
-	return &result, nil
+	retryable := isRetryableStatus(resp.StatusCode) || policy.IsRetryableCode(result.Code)
+	if retryable {
+		c.healthMonitor.Record(uri, time.Since(start), &APIError{Code: result.Code, Message: result.Msg})
+	} else {
+		c.healthMonitor.Record(uri, time.Since(start), nil)
+	}
+
+	return result, retryable, nil
 }
 
 // GenerateRequestID 生成请求ID
@@ -113,6 +219,46 @@ func (c *Client) GetConfig() *Config {
 	return c.config
 }
 
+// Close 关闭客户端：停止后台刷新、域名检测和健康检测，吊销当前token并关闭空闲连接。
+// 配置了TokenStore时token由多个实例共享，不会吊销，需要时请显式调用TokenManager.RevokeToken
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.tokenManager.StopAutoRefresh()
+		c.domainManager.Stop()
+		c.healthMonitor.Stop()
+
+		if c.config.TokenStore == nil {
+			c.closeErr = c.tokenManager.RevokeToken(context.Background())
+		}
+
+		c.httpClient.CloseIdleConnections()
+	})
+	return c.closeErr
+}
+
+// Health 获取健康状态快照，需开启OpenCheckHealthDataSwitch才会统计接口数据
+func (c *Client) Health() *HealthSnapshot {
+	return c.healthMonitor.Snapshot()
+}
+
+// HealthHandler 获取健康检测HTTP处理器，健康时返回200，否则返回503
+func (c *Client) HealthHandler() http.Handler {
+	return c.healthMonitor
+}
+
+// tokenHealth 获取token状态
+func (c *Client) tokenHealth() TokenHealth {
+	return TokenHealth{
+		Valid:      !c.tokenManager.IsTokenExpired(),
+		ExpireTime: c.tokenManager.GetTokenExpireTime(),
+	}
+}
+
+// GetDomainManager 获取域名管理器
+func (c *Client) GetDomainManager() *DomainManager {
+	return c.domainManager
+}
+
 // GetTokenManager 获取令牌管理器（用于测试）
 func (c *Client) GetTokenManager() *TokenManager {
 	return c.tokenManager
@@ -1,6 +1,14 @@
 package getui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -112,13 +120,58 @@ func TestConfigValidate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := tt.config.Validate()
-			if err != tt.wantErr {
+			if (err == nil) != (tt.wantErr == nil) || !errors.Is(err, tt.wantErr) {
 				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestConfigValidate_Aggregated(t *testing.T) {
+	config := NewDefaultConfig()
+	config.AppID = "test_app_id"
+	config.Domain = "restapi.getui.com/v2"
+	config.Domains = []string{"https://", "https://api2.getui.com/v2"}
+	config.SocketTimeout = -1
+	config.CheckHealthInterval = -time.Second
+	config.MaxFailedNum = 2
+	config.ContinuousFailedNum = 5
+	config.ProxyConfig = &HTTPProxyConfig{Host: "127.0.0.1", Scheme: "ftp", Password: "secret"}
+
+	err := config.Validate()
+
+	var configErrs ConfigErrors
+	assertTrue(t, errors.As(err, &configErrs), "应返回ConfigErrors")
+	fields := make(map[string]bool)
+	for _, e := range configErrs {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{
+		"app_key", "master_secret", "domain", "domains[0]", "socket_timeout", "check_health_interval",
+		"continuous_failed_num", "proxy_config.port", "proxy_config.username",
+	} {
+		assertTrue(t, fields[field], "应包含字段错误: "+field)
+	}
+	assertFalse(t, fields["domains[1]"], "合法的域名不应报错")
+	assertTrue(t, errors.Is(err, ErrAppKeyRequired), "应能匹配必填项错误")
+	assertFalse(t, errors.Is(err, ErrAppIDRequired), "已填写的字段不应报错")
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	config := getTestConfig()
+	config.AppKey = ""
+
+	client, err := NewClientWithOptions(config)
+	assertTrue(t, client == nil, "配置无效时不应创建客户端")
+	assertTrue(t, errors.Is(err, ErrAppKeyRequired), "应返回配置错误")
+	assertErrorType(t, err, ConfigErrors{}, "应返回ConfigErrors")
+
+	client, err = NewClientWithOptions(getTestConfig(), func(c *Config) { c.MaxHTTPTryTime = 2 })
+	assertNoError(t, err, "配置有效时不应返回错误")
+	defer client.Close()
+	assertEqual(t, 2, client.GetConfig().MaxHTTPTryTime, "选项应作用于配置")
+}
+
 func TestGenerateRequestID(t *testing.T) {
 	config := getTestConfig()
 	client := NewClient(config)
@@ -187,3 +240,217 @@ func TestApiResultIsSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestDoRequestContext_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.DoRequestContext(ctx, "GET", "/user/count", nil)
+	if err == nil {
+		t.Fatal("expected error for canceled context")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGetTokenContext_Canceled(t *testing.T) {
+	config := getTestConfig()
+	client := NewClient(config)
+	client.GetTokenManager().ClearToken()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetTokenContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// 创建鉴权和业务接口的测试服务器，validToken为空时所有token都无效
+func createTokenTestServer(authCount *int32, validToken string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/auth") {
+			n := atomic.AddInt32(authCount, 1)
+			fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"token":"token_%d"}}`, n)
+			return
+		}
+		if r.Header.Get("token") != validToken {
+			w.Write([]byte(`{"code":10001,"msg":"token invalid"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+}
+
+func TestDoRequest_ReauthOnTokenError(t *testing.T) {
+	var authCount int32
+	server := createTokenTestServer(&authCount, "token_2")
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client := NewClient(config)
+
+	result, err := client.DoRequest("GET", "/user/count", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsSuccess() {
+		t.Errorf("expected success after re-auth, got code=%d", result.Code)
+	}
+	if atomic.LoadInt32(&authCount) != 2 {
+		t.Errorf("expected 2 auth calls, got %d", authCount)
+	}
+	if client.GetTokenManager().GetCurrentToken() != "token_2" {
+		t.Errorf("expected refreshed token to be cached, got %s", client.GetTokenManager().GetCurrentToken())
+	}
+}
+
+func TestDoRequest_TokenErrorAfterReauth(t *testing.T) {
+	var authCount int32
+	server := createTokenTestServer(&authCount, "")
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client := NewClient(config)
+
+	_, err := client.DoRequest("GET", "/user/count", nil)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 10001 {
+		t.Errorf("expected APIError with code 10001, got %v", err)
+	}
+	if atomic.LoadInt32(&authCount) != 2 {
+		t.Errorf("expected exactly one re-auth, got %d auth calls", authCount)
+	}
+	if !client.GetTokenManager().IsTokenExpired() {
+		t.Error("rejected token should be cleared")
+	}
+}
+
+func TestTokenManager_InvalidateToken(t *testing.T) {
+	tokenManager := createTestTokenManager()
+	tokenManager.SetToken("new_token", time.Now().Add(time.Hour))
+
+	tokenManager.InvalidateToken("old_token")
+	if tokenManager.GetCurrentToken() != "new_token" {
+		t.Error("invalidating a stale token should not clear the current token")
+	}
+
+	tokenManager.InvalidateToken("new_token")
+	if !tokenManager.IsTokenExpired() {
+		t.Error("invalidating the current token should clear it")
+	}
+}
+
+// 创建记录吊销请求的测试服务器
+func createRevokeTestServer(revoked *[]string, mu *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" && strings.Contains(r.URL.Path, "/auth/") {
+			mu.Lock()
+			*revoked = append(*revoked, r.URL.Path)
+			mu.Unlock()
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+}
+
+func TestTokenManager_RevokeToken(t *testing.T) {
+	var mu sync.Mutex
+	var revoked []string
+	server := createRevokeTestServer(&revoked, &mu)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.TokenStore = NewMemoryTokenStore()
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+	tokenManager.SetToken("revoke_token", time.Now().Add(time.Hour))
+	config.TokenStore.Set(context.Background(), tokenManager.storeKey(), &StoredToken{Token: "revoke_token", ExpireTime: time.Now().Add(time.Hour)})
+
+	if err := tokenManager.RevokeToken(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "/" + config.AppID + "/auth/revoke_token"
+	if len(revoked) != 1 || revoked[0] != expected {
+		t.Errorf("expected DELETE %s, got %v", expected, revoked)
+	}
+	if !tokenManager.IsTokenExpired() {
+		t.Error("revoked token should be cleared locally")
+	}
+	if stored, _ := config.TokenStore.Get(context.Background(), tokenManager.storeKey()); stored.Valid() {
+		t.Error("revoked token should be cleared from the token store")
+	}
+
+	if err := tokenManager.RevokeToken(context.Background()); err != nil || len(revoked) != 1 {
+		t.Error("revoking without a token should be a no-op")
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	var mu sync.Mutex
+	var revoked []string
+	server := createRevokeTestServer(&revoked, &mu)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.Domains = []string{"http://127.0.0.1:1"}
+	config.TokenAutoRefresh = true
+	config.OpenCheckHealthDataSwitch = true
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("close_token", time.Now().Add(time.Hour))
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("second Close should be a no-op, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(revoked) != 1 {
+		t.Errorf("expected token to be revoked once, got %v", revoked)
+	}
+}
+
+func TestClient_CloseWithTokenStore(t *testing.T) {
+	var mu sync.Mutex
+	var revoked []string
+	server := createRevokeTestServer(&revoked, &mu)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.TokenStore = NewMemoryTokenStore()
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("shared_token", time.Now().Add(time.Hour))
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revoked) != 0 {
+		t.Errorf("shared token should not be revoked on Close, got %v", revoked)
+	}
+}
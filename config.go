@@ -3,7 +3,10 @@ package getui
 import (
 	"bufio"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -16,7 +19,13 @@ type Config struct {
 	AppID        string `json:"app_id"`
 	AppKey       string `json:"app_key"`
 	MasterSecret string `json:"master_secret"`
-	Domain       string `json:"domain"`
+
+	// MasterSecret提供者，为nil时使用MasterSecret，用于在不重建客户端的情况下轮换密钥
+	SecretProvider SecretProvider `json:"-"`
+	Domain         string         `json:"domain"`
+
+	// 候选域名列表，请求失败达到阈值时在Domain和Domains之间切换，开启稳定域名检测时还会定期切换到响应最快的域名
+	Domains []string `json:"domains,omitempty"`
 
 	// HTTP配置
 	SocketTimeout            int  `json:"socket_timeout"`             // HTTP读取超时时间(ms)
@@ -24,6 +33,16 @@ type Config struct {
 	ConnectionRequestTimeout int  `json:"connection_request_timeout"` // 从连接池获取连接超时时间(ms)
 	MaxHTTPTryTime           int  `json:"max_http_try_time"`          // HTTP重试次数
 	TrustSSL                 bool `json:"trust_ssl"`                  // 是否信任SSL证书
+	MaxResponseBodySize      int  `json:"max_response_body_size"`     // 响应体最大字节数，0使用DefaultMaxResponseBodySize
+
+	// token配置
+	TokenExpireMargin     time.Duration `json:"token_expire_margin"`      // 在服务端过期时间之前提前视为过期的余量
+	TokenAutoRefresh      bool          `json:"token_auto_refresh"`       // 是否在后台主动刷新token
+	TokenAutoRefreshAhead time.Duration `json:"token_auto_refresh_ahead"` // 后台刷新token的提前量
+	TokenStore            TokenStore    `json:"-"`                        // 多实例共享token的存储，为nil时只在本地缓存
+
+	// 重试策略，为nil时根据MaxHTTPTryTime使用默认策略
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
 
 	// 域名检测配置
 	OpenAnalyseStableDomain     bool          `json:"open_analyse_stable_domain"`     // 是否开启稳定域名检测
@@ -42,14 +61,22 @@ type Config struct {
 
 	// 自定义超时配置
 	URIToSocketTimeoutMap map[string]int `json:"uri_to_socket_timeout_map"` // URI到超时时间的映射
+
+	// 注入的依赖
+	HTTPClient *http.Client      `json:"-"` // 自定义HTTP客户端，优先于Transport
+	Transport  http.RoundTripper `json:"-"` // 自定义Transport
+	Logger     Logger            `json:"-"` // 日志，为nil时不输出
+	Clock      Clock             `json:"-"` // 时钟，为nil时使用系统时间
 }
 
 // HTTPProxyConfig HTTP代理配置
 type HTTPProxyConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Scheme   string   `json:"scheme"` // 代理协议：http、https或socks5，默认http
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	NoProxy  []string `json:"no_proxy"` // 不走代理的地址，格式同NO_PROXY环境变量
 }
 
 // NewDefaultConfig 创建默认配置
@@ -61,75 +88,161 @@ func NewDefaultConfig() *Config {
 		ConnectionRequestTimeout:    0,
 		MaxHTTPTryTime:              1,
 		TrustSSL:                    false,
+		MaxResponseBodySize:         DefaultMaxResponseBodySize,
 		OpenAnalyseStableDomain:     true,
 		AnalyseStableDomainInterval: 2 * time.Minute,
 		MaxFailedNum:                10,
 		ContinuousFailedNum:         3,
 		CheckMaxFailedNumInterval:   3 * time.Second,
 		HTTPCheckTimeout:            100,
+		TokenExpireMargin:           time.Hour,
+		TokenAutoRefresh:            false,
+		TokenAutoRefreshAhead:       10 * time.Minute,
 		OpenCheckHealthDataSwitch:   false,
 		CheckHealthInterval:         30 * time.Second,
 		URIToSocketTimeoutMap:       make(map[string]int),
 	}
 }
 
-// Validate 验证配置
-func (c *Config) Validate() error {
-	if c.AppID == "" {
-		return ErrAppIDRequired
-	}
-	if c.AppKey == "" {
-		return ErrAppKeyRequired
+// GetHTTPClient 获取HTTP客户端，优先使用注入的HTTPClient或Transport。
+// 读取超时和连接池获取连接超时按请求通过context设置，因此返回的http.Client不设置Timeout
+func (c *Config) GetHTTPClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
 	}
-	if c.MasterSecret == "" {
-		return ErrMasterSecretRequired
+	if c.Transport != nil {
+		return &http.Client{Transport: c.Transport}
 	}
-	if c.Domain == "" {
-		return ErrDomainRequired
+
+	connectTimeout := time.Duration(c.ConnectTimeout) * time.Millisecond
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
 	}
-	return nil
-}
 
-// GetHTTPClient 获取HTTP客户端
-func (c *Config) GetHTTPClient() *http.Client {
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: c.TrustSSL,
 		},
-		DisableKeepAlives: false,
-		IdleConnTimeout:   30 * time.Second,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: connectTimeout,
+		DisableKeepAlives:   false,
+		IdleConnTimeout:     30 * time.Second,
 	}
 
-	// 设置代理
+	// 设置代理，未配置时使用HTTP_PROXY、HTTPS_PROXY、NO_PROXY环境变量
 	if c.ProxyConfig != nil {
-		// 这里可以添加代理配置逻辑
+		transport.Proxy = c.ProxyConfig.ProxyFunc()
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
 	}
 
-	client := &http.Client{
+	return &http.Client{
 		Transport: transport,
-		Timeout:   time.Duration(c.SocketTimeout) * time.Millisecond,
 	}
+}
+
+// GetDomains 获取去重后的候选域名列表，Domain排在首位
+func (c *Config) GetDomains() []string {
+	seen := make(map[string]bool)
+	var domains []string
+	for _, domain := range append([]string{c.Domain}, c.Domains...) {
+		domain = strings.TrimRight(strings.TrimSpace(domain), "/")
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	return domains
+}
 
-	return client
+// clone 深拷贝配置，复制切片、映射以及重试策略和代理配置，注入的依赖保持共享
+func (c *Config) clone() *Config {
+	copied := *c
+	copied.Domains = append([]string(nil), c.Domains...)
+	if c.URIToSocketTimeoutMap != nil {
+		copied.URIToSocketTimeoutMap = make(map[string]int, len(c.URIToSocketTimeoutMap))
+		for uri, timeout := range c.URIToSocketTimeoutMap {
+			copied.URIToSocketTimeoutMap[uri] = timeout
+		}
+	}
+	if c.RetryPolicy != nil {
+		policy := *c.RetryPolicy
+		policy.RetryableCodes = append([]int(nil), c.RetryPolicy.RetryableCodes...)
+		copied.RetryPolicy = &policy
+	}
+	if c.ProxyConfig != nil {
+		proxy := *c.ProxyConfig
+		proxy.NoProxy = append([]string(nil), c.ProxyConfig.NoProxy...)
+		copied.ProxyConfig = &proxy
+	}
+	return &copied
+}
+
+// GetRetryPolicy 获取重试策略
+func (c *Config) GetRetryPolicy() *RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return NewDefaultRetryPolicy(c.MaxHTTPTryTime)
 }
 
-// GetCustomSocketTimeout 获取自定义超时时间
+// GetCustomSocketTimeout 获取自定义超时时间。
+// URIToSocketTimeoutMap的键可以是精确路径，也可以是带参数的模式（如"/task/{task_id}"），
+// 精确路径优先，多个模式匹配时使用参数最少的模式
 func (c *Config) GetCustomSocketTimeout(uri string) int {
-	if timeout, exists := c.URIToSocketTimeoutMap[uri]; exists {
+	path := stripQuery(uri)
+	if timeout, exists := c.URIToSocketTimeoutMap[path]; exists {
 		return timeout
 	}
+
+	bestPattern, bestParams := "", -1
+	for pattern := range c.URIToSocketTimeoutMap {
+		if !matchURIPattern(pattern, path) {
+			continue
+		}
+		params := strings.Count(pattern, "{")
+		if bestParams < 0 || params < bestParams || (params == bestParams && pattern < bestPattern) {
+			bestPattern, bestParams = pattern, params
+		}
+	}
+	if bestParams >= 0 {
+		return c.URIToSocketTimeoutMap[bestPattern]
+	}
+
 	return c.SocketTimeout
 }
 
-// LoadConfigFromEnvFile 从.env文件加载配置
+// LoadConfigFromEnvFile 从.env文件加载配置，读取GETUI_TEST_前缀的变量，
+// 支持的变量见ConfigLoader，值格式错误时返回ConfigErrors
 func LoadConfigFromEnvFile(filename string) (*Config, error) {
+	loader := &ConfigLoader{EnvPrefix: TestEnvPrefix, Files: []string{filename}, IgnoreEnv: true}
+	return loader.Load()
+}
+
+// LoadConfigFromEnvFileOrDefault 从.env文件加载配置，如果文件不存在则返回默认配置。
+// 文件存在但无法读取或值格式错误时输出日志并返回默认配置，需要处理错误时使用LoadConfigFromEnvFile或ConfigLoader
+func LoadConfigFromEnvFileOrDefault(filename string) *Config {
+	config, err := LoadConfigFromEnvFile(filename)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("[getui] failed to load %s, using default config: %v", filename, err)
+		}
+		return NewDefaultConfig()
+	}
+	return config
+}
+
+// readEnvFile 读取.env文件中的KEY=VALUE
+func readEnvFile(filename string) (map[string]string, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("无法打开.env文件: %v", err)
+		return nil, fmt.Errorf("无法打开.env文件: %w", err)
 	}
 	defer file.Close()
 
-	config := NewDefaultConfig()
+	values := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
@@ -150,35 +263,16 @@ func LoadConfigFromEnvFile(filename string) (*Config, error) {
 		value := strings.TrimSpace(parts[1])
 
 		// 移除值两端的引号
-		if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"') {
+		if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"' || value[0] == '\'' && value[len(value)-1] == '\'') {
 			value = value[1 : len(value)-1]
 		}
 
-		switch key {
-		case "GETUI_TEST_APP_ID":
-			config.AppID = value
-		case "GETUI_TEST_APP_KEY":
-			config.AppKey = value
-		case "GETUI_TEST_MASTER_SECRET":
-			config.MasterSecret = value
-		case "GETUI_TEST_DOMAIN":
-			config.Domain = value
-		}
+		values[key] = value
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("读取.env文件时出错: %v", err)
 	}
 
-	return config, nil
-}
-
-// LoadConfigFromEnvFileOrDefault 从.env文件加载配置，如果文件不存在则返回默认配置
-func LoadConfigFromEnvFileOrDefault(filename string) *Config {
-	config, err := LoadConfigFromEnvFile(filename)
-	if err != nil {
-		// 如果文件不存在或读取失败，返回默认配置
-		return NewDefaultConfig()
-	}
-	return config
+	return values, nil
 }
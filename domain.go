@@ -0,0 +1,261 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DomainManager 域名管理器，负责稳定域名检测和故障切换
+type DomainManager struct {
+	config     *Config
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	domains []*domainState
+	current int
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// domainState 单个域名的状态
+type domainState struct {
+	domain           string
+	continuousFailed int           // 连续失败次数
+	totalFailed      int           // 统计窗口内的失败次数
+	windowStart      time.Time     // 失败次数统计窗口的开始时间
+	degraded         bool          // 是否因失败过多被降级
+	latency          time.Duration // 最近一次检测的响应时间
+}
+
+// DomainStatus 域名状态快照
+type DomainStatus struct {
+	Domain           string        `json:"domain"`
+	Current          bool          `json:"current"`
+	Degraded         bool          `json:"degraded"`
+	ContinuousFailed int           `json:"continuous_failed"`
+	TotalFailed      int           `json:"total_failed"`
+	Latency          time.Duration `json:"latency"`
+}
+
+// NewDomainManager 创建新的域名管理器
+func NewDomainManager(config *Config, httpClient *http.Client) *DomainManager {
+	dm := &DomainManager{
+		config:     config,
+		httpClient: httpClient,
+		stopCh:     make(chan struct{}),
+	}
+	for _, domain := range config.GetDomains() {
+		dm.domains = append(dm.domains, &domainState{domain: domain, windowStart: config.now()})
+	}
+	return dm
+}
+
+// Start 启动后台稳定域名检测，仅在开启检测且存在多个候选域名时生效
+func (dm *DomainManager) Start() {
+	if !dm.config.OpenAnalyseStableDomain || len(dm.domains) < 2 {
+		return
+	}
+
+	interval := dm.config.AnalyseStableDomainInterval
+	if interval <= 0 {
+		interval = 2 * time.Minute
+	}
+
+	dm.wg.Add(1)
+	go func() {
+		defer dm.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		dm.Analyse()
+		for {
+			select {
+			case <-dm.stopCh:
+				return
+			case <-ticker.C:
+				dm.Analyse()
+			}
+		}
+	}()
+}
+
+// Stop 停止后台检测
+func (dm *DomainManager) Stop() {
+	dm.stopOnce.Do(func() {
+		close(dm.stopCh)
+	})
+	dm.wg.Wait()
+}
+
+// CurrentDomain 获取当前使用的域名
+func (dm *DomainManager) CurrentDomain() string {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	if len(dm.domains) == 0 {
+		return dm.config.Domain
+	}
+	return dm.domains[dm.current].domain
+}
+
+// ReportSuccess 记录域名请求成功
+func (dm *DomainManager) ReportSuccess(domain string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	if state := dm.find(domain); state != nil {
+		state.continuousFailed = 0
+	}
+}
+
+// ReportFailure 记录域名请求失败，失败次数超过阈值时切换到下一个可用域名
+func (dm *DomainManager) ReportFailure(domain string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	state := dm.find(domain)
+	if state == nil {
+		return
+	}
+
+	now := dm.config.now()
+	if interval := dm.config.CheckMaxFailedNumInterval; interval > 0 && now.Sub(state.windowStart) > interval {
+		state.totalFailed = 0
+		state.windowStart = now
+	}
+	state.continuousFailed++
+	state.totalFailed++
+
+	continuousExceeded := dm.config.ContinuousFailedNum > 0 && state.continuousFailed >= dm.config.ContinuousFailedNum
+	totalExceeded := dm.config.MaxFailedNum > 0 && state.totalFailed >= dm.config.MaxFailedNum
+	if !continuousExceeded && !totalExceeded {
+		return
+	}
+
+	state.degraded = true
+	if dm.domains[dm.current] == state {
+		dm.switchNext()
+		dm.config.logf("domain %s degraded, switched to %s", domain, dm.domains[dm.current].domain)
+	}
+}
+
+// Analyse 检测所有候选域名，切换到响应最快的可用域名
+func (dm *DomainManager) Analyse() {
+	dm.mu.RLock()
+	domains := make([]string, len(dm.domains))
+	for i, state := range dm.domains {
+		domains[i] = state.domain
+	}
+	dm.mu.RUnlock()
+
+	latencies := make([]time.Duration, len(domains))
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+			latencies[i] = dm.probe(domain)
+		}(i, domain)
+	}
+	wg.Wait()
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	best := -1
+	for i, state := range dm.domains {
+		state.latency = latencies[i]
+		if latencies[i] < 0 {
+			continue
+		}
+		// 检测可达的域名解除降级
+		state.degraded = false
+		state.continuousFailed = 0
+		if best < 0 || latencies[i] < latencies[best] {
+			best = i
+		}
+	}
+	if best >= 0 {
+		dm.current = best
+	}
+}
+
+// Status 获取所有域名的状态快照
+func (dm *DomainManager) Status() []DomainStatus {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	status := make([]DomainStatus, len(dm.domains))
+	for i, state := range dm.domains {
+		status[i] = DomainStatus{
+			Domain:           state.domain,
+			Current:          i == dm.current,
+			Degraded:         state.degraded,
+			ContinuousFailed: state.continuousFailed,
+			TotalFailed:      state.totalFailed,
+			Latency:          state.latency,
+		}
+	}
+	return status
+}
+
+// probe 检测单个域名，返回响应时间，不可达或返回5xx时返回-1
+func (dm *DomainManager) probe(domain string) time.Duration {
+	timeout := time.Duration(dm.config.HTTPCheckTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", domain, nil)
+	if err != nil {
+		return -1
+	}
+
+	start := time.Now()
+	resp, err := dm.httpClient.Do(req)
+	if err != nil {
+		return -1
+	}
+	latency := time.Since(start)
+	resp.Body.Close()
+
+	// 网关返回5xx说明后端不可用，其余响应认为域名可达
+	if isRetryableStatus(resp.StatusCode) {
+		return -1
+	}
+	return latency
+}
+
+// find 查找域名状态，调用方需持有锁
+func (dm *DomainManager) find(domain string) *domainState {
+	for _, state := range dm.domains {
+		if state.domain == domain {
+			return state
+		}
+	}
+	return nil
+}
+
+// switchNext 切换到下一个未降级的域名，全部降级时重置降级状态并轮换，调用方需持有写锁
+func (dm *DomainManager) switchNext() {
+	for i := 1; i < len(dm.domains); i++ {
+		next := (dm.current + i) % len(dm.domains)
+		if !dm.domains[next].degraded {
+			dm.current = next
+			return
+		}
+	}
+
+	for _, state := range dm.domains {
+		state.degraded = false
+	}
+	dm.current = (dm.current + 1) % len(dm.domains)
+}
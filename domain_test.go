@@ -0,0 +1,135 @@
+package getui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 创建域名管理器测试配置
+func createDomainTestConfig(domains ...string) *Config {
+	config := NewDefaultConfig()
+	config.Domain = domains[0]
+	config.Domains = domains[1:]
+	config.ContinuousFailedNum = 3
+	config.MaxFailedNum = 5
+	config.CheckMaxFailedNumInterval = time.Minute
+	return config
+}
+
+func TestConfig_GetDomains(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Domains = []string{"https://a.getui.com/v2/", config.Domain, " ", "https://b.getui.com/v2"}
+
+	assertEqual(t, []string{"https://restapi.getui.com/v2", "https://a.getui.com/v2", "https://b.getui.com/v2"},
+		config.GetDomains(), "候选域名应该去重并保持顺序")
+}
+
+func TestDomainManager_ContinuousFailureSwitch(t *testing.T) {
+	config := createDomainTestConfig("https://a.getui.com/v2", "https://b.getui.com/v2")
+	dm := NewDomainManager(config, http.DefaultClient)
+
+	assertEqual(t, "https://a.getui.com/v2", dm.CurrentDomain(), "初始应使用第一个域名")
+
+	dm.ReportFailure("https://a.getui.com/v2")
+	dm.ReportFailure("https://a.getui.com/v2")
+	dm.ReportSuccess("https://a.getui.com/v2")
+	dm.ReportFailure("https://a.getui.com/v2")
+	dm.ReportFailure("https://a.getui.com/v2")
+	assertEqual(t, "https://a.getui.com/v2", dm.CurrentDomain(), "成功后连续失败次数应重置")
+
+	dm.ReportFailure("https://a.getui.com/v2")
+	assertEqual(t, "https://b.getui.com/v2", dm.CurrentDomain(), "连续失败达到阈值应切换域名")
+
+	status := dm.Status()
+	assertTrue(t, status[0].Degraded, "失败的域名应被降级")
+	assertTrue(t, status[1].Current, "切换后的域名应为当前域名")
+}
+
+func TestDomainManager_MaxFailedSwitch(t *testing.T) {
+	config := createDomainTestConfig("https://a.getui.com/v2", "https://b.getui.com/v2")
+	config.ContinuousFailedNum = 0
+	dm := NewDomainManager(config, http.DefaultClient)
+
+	for i := 0; i < 4; i++ {
+		dm.ReportFailure("https://a.getui.com/v2")
+		dm.ReportSuccess("https://a.getui.com/v2")
+	}
+	assertEqual(t, "https://a.getui.com/v2", dm.CurrentDomain(), "未达到最大失败次数不应切换")
+
+	dm.ReportFailure("https://a.getui.com/v2")
+	assertEqual(t, "https://b.getui.com/v2", dm.CurrentDomain(), "达到最大失败次数应切换域名")
+}
+
+func TestDomainManager_Analyse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	config := createDomainTestConfig(closed.URL, server.URL)
+	config.HTTPCheckTimeout = 1000
+	dm := NewDomainManager(config, http.DefaultClient)
+
+	dm.Analyse()
+
+	assertEqual(t, server.URL, dm.CurrentDomain(), "应切换到可达的域名")
+}
+
+func TestDomainManager_AnalyseSkipsServerError(t *testing.T) {
+	badGateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer badGateway.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := createDomainTestConfig(badGateway.URL, server.URL)
+	config.ContinuousFailedNum = 1
+	config.HTTPCheckTimeout = 1000
+	dm := NewDomainManager(config, http.DefaultClient)
+
+	dm.ReportFailure(badGateway.URL)
+	assertEqual(t, server.URL, dm.CurrentDomain(), "失败后应切换域名")
+
+	dm.Analyse()
+
+	status := dm.Status()
+	assertEqual(t, server.URL, dm.CurrentDomain(), "返回502的域名即使响应更快也不应被选中")
+	assertTrue(t, status[0].Degraded, "返回502的域名不应解除降级")
+	assertEqual(t, time.Duration(-1), status[0].Latency, "返回502的域名应视为不可达")
+}
+
+func TestDoRequest_DomainFailover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	config := getTestConfig()
+	config.Domain = closed.URL
+	config.Domains = []string{server.URL}
+	config.ContinuousFailedNum = 1
+	config.OpenAnalyseStableDomain = false
+	config.RetryPolicy = &RetryPolicy{MaxAttempts: 2}
+
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+	result, err := client.DoRequest("GET", "/user/count", nil)
+
+	assertNoError(t, err, "切换域名后重试应该成功")
+	assertTrue(t, result.IsSuccess(), "切换域名后结果应该成功")
+	assertEqual(t, server.URL, client.GetDomainManager().CurrentDomain(), "应切换到可用域名")
+}
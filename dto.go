@@ -1,5 +1,10 @@
 package getui
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // AuthDTO 认证请求DTO
 type AuthDTO struct {
 	Sign      string `json:"sign"`
@@ -7,13 +12,28 @@ type AuthDTO struct {
 	AppKey    string `json:"appkey"`
 }
 
+// AuthResultDTO 鉴权响应DTO
+type AuthResultDTO struct {
+	Token      string      `json:"token"`
+	ExpireTime json.Number `json:"expire_time"` // 过期时间，毫秒时间戳
+}
+
+// ExpireAt 获取服务端返回的过期时间
+func (r *AuthResultDTO) ExpireAt() (time.Time, error) {
+	ms, err := r.ExpireTime.Int64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
 // PushDTO 推送请求DTO
 type PushDTO struct {
 	RequestID   string       `json:"request_id"`
 	TaskName    string       `json:"task_name,omitempty"`
 	GroupName   string       `json:"group_name,omitempty"`
 	Settings    *Settings    `json:"settings,omitempty"`
-	Audience    interface{}  `json:"audience"`
+	Audience    *Audience    `json:"audience"`
 	PushMessage *PushMessage `json:"push_message"`
 	PushChannel *PushChannel `json:"push_channel,omitempty"`
 }
@@ -24,27 +44,40 @@ type PushBatchDTO struct {
 	TaskName    string       `json:"task_name,omitempty"`
 	GroupName   string       `json:"group_name,omitempty"`
 	Settings    *Settings    `json:"settings,omitempty"`
-	Audience    interface{}  `json:"audience"`
+	Audience    *Audience    `json:"audience"`
 	PushMessage *PushMessage `json:"push_message"`
 	PushChannel *PushChannel `json:"push_channel,omitempty"`
 }
 
 // AudienceDTO 受众DTO
 type AudienceDTO struct {
-	RequestID string      `json:"request_id"`
-	TaskName  string      `json:"task_name,omitempty"`
-	GroupName string      `json:"group_name,omitempty"`
-	Settings  *Settings   `json:"settings,omitempty"`
-	Audience  interface{} `json:"audience"`
+	RequestID string    `json:"request_id"`
+	TaskName  string    `json:"task_name,omitempty"`
+	GroupName string    `json:"group_name,omitempty"`
+	Settings  *Settings `json:"settings,omitempty"`
+	Audience  *Audience `json:"audience"`
+}
+
+// AliasBinding 别名与CID的绑定关系
+type AliasBinding struct {
+	CID   string `json:"cid"`
+	Alias string `json:"alias"`
 }
 
-// Audience 受众
-type Audience struct {
-	CIDs   []string `json:"cid,omitempty"`
-	Alias  []string `json:"alias,omitempty"`
-	Tag    []string `json:"tag,omitempty"`
-	All    string   `json:"all,omitempty"`
-	FileID string   `json:"file_id,omitempty"`
+// aliasBatchDTO 批量绑定别名请求
+type aliasBatchDTO struct {
+	DataList []AliasBinding `json:"data_list"`
+}
+
+// userTagDTO 用户标签请求
+type userTagDTO struct {
+	CID  string   `json:"cid"`
+	Tags []string `json:"tags"`
+}
+
+// userStatusDTO 查询用户状态请求
+type userStatusDTO struct {
+	CIDs []string `json:"cid"`
 }
 
 // PushMessage 推送消息
@@ -69,6 +102,7 @@ type Notification struct {
 	Buzz         int               `json:"buzz,omitempty"`
 	Logo         string            `json:"logo,omitempty"`
 	LogoURL      string            `json:"logo_url,omitempty"`
+	BigImage     string            `json:"big_image,omitempty"`
 	ChannelID    string            `json:"channel_id,omitempty"`
 	ChannelName  string            `json:"channel_name,omitempty"`
 	ChannelLevel int               `json:"channel_level,omitempty"`
@@ -91,14 +125,22 @@ type PushChannel struct {
 
 // IOSDTO iOS推送参数
 type IOSDTO struct {
-	Type             string `json:"type,omitempty"`
-	APNS             *APNS  `json:"apns,omitempty"`
-	APNSCollapseID   string `json:"apns_collapse_id,omitempty"`
-	AutoBadge        string `json:"auto_badge,omitempty"`
-	MutableContent   int    `json:"mutable_content,omitempty"`
-	ContentAvailable int    `json:"content_available,omitempty"`
-	Category         string `json:"category,omitempty"`
-	Alert            *Alert `json:"alert,omitempty"`
+	Type             string        `json:"type,omitempty"`
+	APNS             *APNS         `json:"apns,omitempty"`
+	APNSCollapseID   string        `json:"apns_collapse_id,omitempty"`
+	AutoBadge        string        `json:"auto_badge,omitempty"`
+	MutableContent   int           `json:"mutable_content,omitempty"`
+	ContentAvailable int           `json:"content_available,omitempty"`
+	Category         string        `json:"category,omitempty"`
+	Alert            *Alert        `json:"alert,omitempty"`
+	Multimedia       []*Multimedia `json:"multimedia,omitempty"`
+}
+
+// Multimedia iOS多媒体资源
+type Multimedia struct {
+	URL      string `json:"url"`
+	Type     int    `json:"type"` // 1：图片，2：音频，3：视频
+	OnlyWifi bool   `json:"only_wifi,omitempty"`
 }
 
 // APNS APNS配置
@@ -184,7 +226,7 @@ type Strategy struct {
 
 // TaskIDDTO 任务ID响应
 type TaskIDDTO struct {
-	TaskID string `json:"task_id"`
+	TaskID string `json:"taskid"`
 }
 
 // ScheduleTaskDTO 定时任务
@@ -202,6 +244,8 @@ type CidStatusDTO struct {
 }
 
 // StatisticDTO 统计数据
+//
+// Deprecated: StatisticAPI返回PushReport、UserReport等完整的报表模型
 type StatisticDTO struct {
 	TaskID       string `json:"task_id"`
 	SendCount    int    `json:"send_count"`
@@ -0,0 +1,138 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrorCategory 个推返回码分类
+type ErrorCategory int
+
+const (
+	ErrorCategoryUnknown      ErrorCategory = iota // 未知
+	ErrorCategoryAuth                              // 鉴权失败或无权限
+	ErrorCategoryRateLimit                         // 频率超限
+	ErrorCategoryInvalidParam                      // 参数错误
+	ErrorCategoryInvalidCID                        // CID或别名无效
+	ErrorCategoryServer                            // 服务端异常
+	ErrorCategoryQuota                             // 每日配额超限
+)
+
+// String 返回分类名称
+func (c ErrorCategory) String() string {
+	switch c {
+	case ErrorCategoryAuth:
+		return "auth"
+	case ErrorCategoryRateLimit:
+		return "rate_limit"
+	case ErrorCategoryInvalidParam:
+		return "invalid_param"
+	case ErrorCategoryInvalidCID:
+		return "invalid_cid"
+	case ErrorCategoryServer:
+		return "server"
+	case ErrorCategoryQuota:
+		return "quota"
+	default:
+		return "unknown"
+	}
+}
+
+// Err 获取分类对应的错误哨兵，未知分类返回nil
+func (c ErrorCategory) Err() error {
+	switch c {
+	case ErrorCategoryAuth:
+		return ErrUnauthorized
+	case ErrorCategoryRateLimit:
+		return ErrRateLimited
+	case ErrorCategoryInvalidParam:
+		return ErrInvalidParam
+	case ErrorCategoryInvalidCID:
+		return ErrInvalidTarget
+	case ErrorCategoryServer:
+		return ErrServerError
+	case ErrorCategoryQuota:
+		return ErrQuotaExceeded
+	default:
+		return nil
+	}
+}
+
+// Retryable 判断该分类的错误是否可以重试，配额超限当天重试也不会成功
+func (c ErrorCategory) Retryable() bool {
+	return c == ErrorCategoryRateLimit || c == ErrorCategoryServer
+}
+
+// ErrorCode 个推返回码说明
+type ErrorCode struct {
+	Code        int
+	Category    ErrorCategory
+	Description string
+}
+
+var (
+	errorCodesMu sync.RWMutex
+	// errorCodes 个推RestAPI V2返回码目录
+	errorCodes = map[int]ErrorCode{
+		10001: {10001, ErrorCategoryAuth, "token错误或失效"},
+		10002: {10002, ErrorCategoryAuth, "appId或ip在黑名单中"},
+		10003: {10003, ErrorCategoryRateLimit, "每分钟鉴权频率超限"},
+		10004: {10004, ErrorCategoryAuth, "没有查询消息明细的权限"},
+		10005: {10005, ErrorCategoryRateLimit, "每分钟调用频率超限"},
+		10006: {10006, ErrorCategoryQuota, "推送总量超过每日限额"},
+		20001: {20001, ErrorCategoryInvalidParam, "缺少必要参数或参数不合法"},
+		20002: {20002, ErrorCategoryInvalidCID, "cid无效"},
+		20003: {20003, ErrorCategoryInvalidCID, "别名无效或未绑定cid"},
+		50000: {50000, ErrorCategoryServer, "系统内部异常"},
+		50001: {50001, ErrorCategoryServer, "服务繁忙"},
+	}
+)
+
+// LookupErrorCode 查询返回码说明，目录中没有的返回码按号段分类：2xxxx为参数错误，5xxxx为服务端异常
+func LookupErrorCode(code int) ErrorCode {
+	errorCodesMu.RLock()
+	info, ok := errorCodes[code]
+	errorCodesMu.RUnlock()
+	if ok {
+		return info
+	}
+
+	info = ErrorCode{Code: code}
+	switch code / 10000 {
+	case 2:
+		info.Category = ErrorCategoryInvalidParam
+	case 5:
+		info.Category = ErrorCategoryServer
+	}
+	return info
+}
+
+// RegisterErrorCode 注册或覆盖返回码的分类，用于补充目录中没有的返回码
+func RegisterErrorCode(code int, category ErrorCategory, description string) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+	errorCodes[code] = ErrorCode{Code: code, Category: category, Description: description}
+}
+
+// IsRetryable 判断错误是否可以重试：网络错误、服务端异常和频率超限可以重试，
+// 鉴权失败、参数错误、配额超限和ctx取消不可重试
+func IsRetryable(err error) bool {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, ErrServerError), errors.Is(err, ErrRateLimited):
+		return true
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrInvalidParam), errors.Is(err, ErrInvalidTarget),
+		errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrHTTPRequestFailed), errors.Is(err, ErrInvalidResponse):
+		return false
+	}
+
+	// 未知分类的返回码不重试，其余网络错误可以重试
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return false
+	}
+	var netErr *NetworkError
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,106 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		code     int
+		sentinel error
+		category ErrorCategory
+	}{
+		{10001, ErrUnauthorized, ErrorCategoryAuth},
+		{10005, ErrRateLimited, ErrorCategoryRateLimit},
+		{20001, ErrInvalidParam, ErrorCategoryInvalidParam},
+		{10006, ErrQuotaExceeded, ErrorCategoryQuota},
+		{20002, ErrInvalidTarget, ErrorCategoryInvalidCID},
+		{20003, ErrInvalidTarget, ErrorCategoryInvalidCID},
+		{50000, ErrServerError, ErrorCategoryServer},
+		{59999, ErrServerError, ErrorCategoryServer},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("push failed: %w", &APIError{Code: tt.code, Message: "msg"})
+		assertTrue(t, errors.Is(err, tt.sentinel), fmt.Sprintf("返回码%d应匹配%v", tt.code, tt.sentinel))
+		assertEqual(t, tt.category, LookupErrorCode(tt.code).Category, fmt.Sprintf("返回码%d的分类", tt.code))
+	}
+
+	err := error(&APIError{Code: 10005})
+	assertTrue(t, errors.Is(err, &APIError{Code: 10005}), "返回码相同的APIError应匹配")
+	assertFalse(t, errors.Is(err, &APIError{Code: 10003}), "返回码不同的APIError不应匹配")
+	assertFalse(t, errors.Is(&APIError{Code: 99999}, ErrServerError), "未知返回码不应匹配任何分类")
+	assertFalse(t, errors.Is(&APIError{Code: 10006}, ErrRateLimited), "配额超限不应匹配频率超限")
+	assertFalse(t, errors.Is(&APIError{Code: 20002}, ErrInvalidCID), "CID无效不应匹配客户端的空CID校验错误")
+
+	assertTrue(t, errors.Is(newTokenError(&ApiResult{Code: 10001, Msg: "token expired"}), ErrUnauthorized), "token错误应属于鉴权失败")
+
+	RegisterErrorCode(99999, ErrorCategoryRateLimit, "测试返回码")
+	t.Cleanup(func() {
+		errorCodesMu.Lock()
+		delete(errorCodes, 99999)
+		errorCodesMu.Unlock()
+	})
+	assertTrue(t, errors.Is(&APIError{Code: 99999}, ErrRateLimited), "注册后应按新分类匹配")
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"服务端异常", &APIError{Code: 50000}, true},
+		{"频率超限", fmt.Errorf("wrap: %w", &APIError{Code: 10005}), true},
+		{"鉴权失败", &APIError{Code: 10001}, false},
+		{"参数错误", &APIError{Code: 20001}, false},
+		{"配额超限", &APIError{Code: 10006}, false},
+		{"CID无效", &APIError{Code: 20002}, false},
+		{"未知返回码", &APIError{Code: 1}, false},
+		{"网络错误", &NetworkError{Message: "failed to send request", Cause: errors.New("connection refused")}, true},
+		{"连接池超时", &NetworkError{Message: "failed to send request", Cause: ErrConnectionRequestTimeout}, true},
+		{"ctx取消", &NetworkError{Message: "failed to send request", Cause: context.Canceled}, false},
+		{"HTTP 503", &HTTPError{StatusCode: http.StatusServiceUnavailable, Err: statusSentinel(http.StatusServiceUnavailable)}, true},
+		{"HTTP 429", &HTTPError{StatusCode: http.StatusTooManyRequests, Err: statusSentinel(http.StatusTooManyRequests)}, true},
+		{"HTTP 401", &HTTPError{StatusCode: http.StatusUnauthorized, Err: statusSentinel(http.StatusUnauthorized)}, false},
+		{"响应格式错误", &HTTPError{StatusCode: http.StatusOK, Err: ErrInvalidResponse}, false},
+		{"客户端校验", ErrEmptyAudience, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.expected, IsRetryable(tt.err), "IsRetryable")
+		})
+	}
+}
+
+func TestDoRequest_StatusErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		if r.URL.Path == "/"+getTestConfig().AppID+"/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 3)
+
+	_, err := client.DoRequest("GET", "/limited", nil)
+	assertTrue(t, errors.Is(err, ErrRateLimited), "429应返回ErrRateLimited")
+	assertEqual(t, int32(3), atomic.LoadInt32(&attempts), "429应该重试")
+
+	atomic.StoreInt32(&attempts, 0)
+	_, err = client.DoRequest("GET", "/bad", nil)
+	assertTrue(t, errors.Is(err, ErrHTTPRequestFailed), "400应返回ErrHTTPRequestFailed")
+	assertEqual(t, int32(1), atomic.LoadInt32(&attempts), "400不应该重试")
+}
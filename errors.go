@@ -3,6 +3,7 @@ package getui
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // 配置相关错误
@@ -11,15 +12,27 @@ var (
 	ErrAppKeyRequired       = errors.New("app_key is required")
 	ErrMasterSecretRequired = errors.New("master_secret is required")
 	ErrDomainRequired       = errors.New("domain is required")
+
+	ErrUnsupportedConfigFormat = errors.New("unsupported config file format")
+)
+
+// 多应用相关错误
+var (
+	ErrUnknownTenant  = errors.New("unknown tenant")
+	ErrTenantExists   = errors.New("tenant already registered")
+	ErrRegistryClosed = errors.New("client registry closed")
 )
 
 // API相关错误
 var (
-	ErrInvalidRequestID = errors.New("request_id must be between 10-32 characters")
-	ErrEmptyAudience    = errors.New("audience cannot be empty")
-	ErrEmptyPushMessage = errors.New("push_message cannot be empty")
-	ErrInvalidCID       = errors.New("cid cannot be empty")
-	ErrInvalidAlias     = errors.New("alias cannot be empty")
+	ErrInvalidRequestID   = errors.New("request_id must be between 10-32 characters")
+	ErrEmptyAudience      = errors.New("audience cannot be empty")
+	ErrInvalidAudience    = errors.New("invalid audience")
+	ErrEmptyPushMessage   = errors.New("push_message cannot be empty")
+	ErrInvalidPushMessage = errors.New("invalid push message")
+	ErrInvalidCID         = errors.New("cid cannot be empty")
+	ErrInvalidAlias       = errors.New("alias cannot be empty")
+	ErrUserNotFound       = errors.New("user not found")
 )
 
 // HTTP相关错误
@@ -28,14 +41,43 @@ var (
 	ErrInvalidResponse   = errors.New("invalid response")
 	ErrUnauthorized      = errors.New("unauthorized")
 	ErrRateLimited       = errors.New("rate limited")
+	ErrInvalidParam      = errors.New("invalid parameter")
+	ErrInvalidTarget     = errors.New("invalid cid or alias")
+	ErrQuotaExceeded     = errors.New("daily quota exceeded")
+	ErrServerError       = errors.New("server error")
+	ErrResponseTooLarge  = fmt.Errorf("%w: response body too large", ErrInvalidResponse)
+
+	ErrConnectionRequestTimeout = errors.New("connection request timeout")
 )
 
 // 认证相关错误
 var (
-	ErrTokenExpired = errors.New("token expired")
-	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrTokenStoreLocked = errors.New("token store locked")
 )
 
+// tokenErrorCodes 表示token无效的个推返回码
+var tokenErrorCodes = map[int]bool{
+	10001: true, // token错误或失效
+}
+
+// isTokenErrorCode 判断返回码是否表示token无效
+func isTokenErrorCode(code int) bool {
+	return tokenErrorCodes[code]
+}
+
+// newTokenError 根据返回结果生成token错误，
+// 提示过期的返回ErrTokenExpired，否则返回ErrInvalidToken，均可通过errors.As获取APIError
+func newTokenError(result *ApiResult) error {
+	sentinel := ErrInvalidToken
+	msg := strings.ToLower(result.Msg)
+	if strings.Contains(msg, "expire") || strings.Contains(msg, "过期") {
+		sentinel = ErrTokenExpired
+	}
+	return fmt.Errorf("%w: %w", sentinel, &APIError{Code: result.Code, Message: result.Msg})
+}
+
 // 自定义错误类型
 type APIError struct {
 	Code    int    `json:"code"`
@@ -46,6 +88,22 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error: code=%d, message=%s", e.Code, e.Message)
 }
 
+// Category 获取返回码分类
+func (e *APIError) Category() ErrorCategory {
+	return LookupErrorCode(e.Code).Category
+}
+
+// Is 返回码相同的APIError视为同一错误，如errors.Is(err, &APIError{Code: 10005})
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+// Unwrap 返回分类对应的错误哨兵，可通过errors.Is(err, ErrRateLimited)等判断错误类别
+func (e *APIError) Unwrap() error {
+	return e.Category().Err()
+}
+
 // 网络错误
 type NetworkError struct {
 	Message string
@@ -67,8 +125,32 @@ func (e *NetworkError) Unwrap() error {
 type ConfigError struct {
 	Field   string
 	Message string
+	Err     error // 对应的错误哨兵，可为nil
 }
 
 func (e *ConfigError) Error() string {
 	return fmt.Sprintf("config error: field=%s, message=%s", e.Field, e.Message)
 }
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors 配置校验发现的全部错误，可通过errors.Is/errors.As检查其中任意一项
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
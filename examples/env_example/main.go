@@ -36,9 +36,7 @@ func main() {
 	pushDTO := &getui.PushDTO{
 		RequestID:   client.GenerateRequestID(),
 		PushMessage: pushMessage,
-		Audience: &getui.Audience{
-			CIDs: []string{"test_cid_123"},
-		},
+		Audience:    getui.ByCIDs("test_cid_123"),
 	}
 
 	fmt.Println("📤 发送测试推送...")
@@ -54,11 +52,7 @@ func main() {
 		return
 	}
 
-	if result.IsSuccess() {
-		fmt.Printf("✅ 推送成功: %+v\n", result.Data)
-	} else {
-		fmt.Printf("❌ 推送失败: code=%d, msg=%s\n", result.Code, result.Msg)
-	}
+	fmt.Printf("✅ 推送成功: taskid=%s, status=%v\n", result.TaskID(), result.PerCIDStatus())
 }
 
 // maskSecret 隐藏敏感信息
@@ -0,0 +1,237 @@
+package getui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultHealthErrorRateThreshold 默认的健康错误率阈值，超过该值认为推送不可用
+const DefaultHealthErrorRateThreshold = 0.5
+
+// HealthMonitor 健康检测器，按接口统计请求延迟、错误率以及token状态
+type HealthMonitor struct {
+	config *Config
+
+	mu       sync.Mutex
+	current  map[string]*endpointStats // 当前统计窗口
+	previous map[string]*endpointStats // 上一个统计窗口
+
+	tokenStatus func() TokenHealth
+	domain      func() string
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// endpointStats 单个接口在统计窗口内的数据
+type endpointStats struct {
+	requests        int64
+	errors          int64
+	totalLatency    time.Duration
+	lastLatency     time.Duration
+	lastError       string
+	lastErrorTime   time.Time
+	lastSuccessTime time.Time
+}
+
+// HealthSnapshot 健康状态快照
+type HealthSnapshot struct {
+	Healthy   bool                      `json:"healthy"`
+	Enabled   bool                      `json:"enabled"`
+	Time      time.Time                 `json:"time"`
+	Domain    string                    `json:"domain"`
+	ErrorRate float64                   `json:"error_rate"`
+	Token     TokenHealth               `json:"token"`
+	Endpoints map[string]EndpointHealth `json:"endpoints"`
+}
+
+// TokenHealth token状态
+type TokenHealth struct {
+	Valid      bool      `json:"valid"`
+	ExpireTime time.Time `json:"expire_time"`
+}
+
+// EndpointHealth 单个接口的健康数据
+type EndpointHealth struct {
+	Requests        int64         `json:"requests"`
+	Errors          int64         `json:"errors"`
+	ErrorRate       float64       `json:"error_rate"`
+	AvgLatency      time.Duration `json:"avg_latency"`
+	LastLatency     time.Duration `json:"last_latency"`
+	LastError       string        `json:"last_error,omitempty"`
+	LastErrorTime   time.Time     `json:"last_error_time,omitempty"`
+	LastSuccessTime time.Time     `json:"last_success_time,omitempty"`
+}
+
+// NewHealthMonitor 创建新的健康检测器，tokenStatus和domain用于获取当前token状态和域名
+func NewHealthMonitor(config *Config, tokenStatus func() TokenHealth, domain func() string) *HealthMonitor {
+	return &HealthMonitor{
+		config:      config,
+		current:     make(map[string]*endpointStats),
+		previous:    make(map[string]*endpointStats),
+		tokenStatus: tokenStatus,
+		domain:      domain,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Enabled 是否开启健康检测
+func (hm *HealthMonitor) Enabled() bool {
+	return hm != nil && hm.config.OpenCheckHealthDataSwitch
+}
+
+// Start 启动后台健康检测循环，每个检测周期滚动一次统计窗口
+func (hm *HealthMonitor) Start() {
+	if !hm.Enabled() {
+		return
+	}
+
+	interval := hm.config.CheckHealthInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	hm.wg.Add(1)
+	go func() {
+		defer hm.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-hm.stopCh:
+				return
+			case <-ticker.C:
+				hm.rotate()
+			}
+		}
+	}()
+}
+
+// Stop 停止后台健康检测
+func (hm *HealthMonitor) Stop() {
+	if hm == nil {
+		return
+	}
+	hm.stopOnce.Do(func() {
+		close(hm.stopCh)
+	})
+	hm.wg.Wait()
+}
+
+// Record 记录一次接口请求结果
+func (hm *HealthMonitor) Record(uri string, latency time.Duration, err error) {
+	if !hm.Enabled() {
+		return
+	}
+
+	endpoint := endpointOf(uri)
+	now := time.Now()
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	stats, ok := hm.current[endpoint]
+	if !ok {
+		stats = &endpointStats{}
+		hm.current[endpoint] = stats
+	}
+
+	stats.requests++
+	stats.totalLatency += latency
+	stats.lastLatency = latency
+	if err != nil {
+		stats.errors++
+		stats.lastError = err.Error()
+		stats.lastErrorTime = now
+	} else {
+		stats.lastSuccessTime = now
+	}
+}
+
+// Snapshot 获取当前健康状态快照，统计数据覆盖当前和上一个统计窗口
+func (hm *HealthMonitor) Snapshot() *HealthSnapshot {
+	snapshot := &HealthSnapshot{
+		Enabled:   hm.Enabled(),
+		Time:      time.Now(),
+		Endpoints: make(map[string]EndpointHealth),
+	}
+	if hm.domain != nil {
+		snapshot.Domain = hm.domain()
+	}
+	if hm.tokenStatus != nil {
+		snapshot.Token = hm.tokenStatus()
+	}
+
+	hm.mu.Lock()
+	var requests, errors int64
+	for _, window := range []map[string]*endpointStats{hm.previous, hm.current} {
+		for endpoint, stats := range window {
+			health := snapshot.Endpoints[endpoint]
+			totalLatency := health.AvgLatency * time.Duration(health.Requests)
+
+			health.Requests += stats.requests
+			health.Errors += stats.errors
+			totalLatency += stats.totalLatency
+			if health.Requests > 0 {
+				health.AvgLatency = totalLatency / time.Duration(health.Requests)
+				health.ErrorRate = float64(health.Errors) / float64(health.Requests)
+			}
+			health.LastLatency = stats.lastLatency
+			if stats.lastError != "" {
+				health.LastError = stats.lastError
+				health.LastErrorTime = stats.lastErrorTime
+			}
+			if !stats.lastSuccessTime.IsZero() {
+				health.LastSuccessTime = stats.lastSuccessTime
+			}
+			snapshot.Endpoints[endpoint] = health
+
+			requests += stats.requests
+			errors += stats.errors
+		}
+	}
+	hm.mu.Unlock()
+
+	if requests > 0 {
+		snapshot.ErrorRate = float64(errors) / float64(requests)
+	}
+	snapshot.Healthy = snapshot.ErrorRate < DefaultHealthErrorRateThreshold && hm.tokenDeliverable(snapshot)
+
+	return snapshot
+}
+
+// ServeHTTP 以JSON输出健康状态，健康时返回200，否则返回503，可直接用作就绪探针
+func (hm *HealthMonitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := hm.Snapshot()
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	if snapshot.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// tokenDeliverable 判断token状态是否允许推送：token有效，或最近一次鉴权没有失败
+func (hm *HealthMonitor) tokenDeliverable(snapshot *HealthSnapshot) bool {
+	if snapshot.Token.Valid {
+		return true
+	}
+	auth, ok := snapshot.Endpoints["/auth"]
+	return !ok || !auth.LastErrorTime.After(auth.LastSuccessTime)
+}
+
+// rotate 滚动统计窗口
+func (hm *HealthMonitor) rotate() {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	hm.previous = hm.current
+	hm.current = make(map[string]*endpointStats)
+}
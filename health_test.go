@@ -0,0 +1,107 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 创建开启健康检测的测试客户端
+func createHealthTestClient(serverURL string) *Client {
+	config := getTestConfig()
+	config.Domain = serverURL
+	config.OpenCheckHealthDataSwitch = true
+	config.CheckHealthInterval = time.Hour
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+	return client
+}
+
+func TestEndpointOf(t *testing.T) {
+	assertEqual(t, "/push/single/cid", endpointOf("/push/single/cid"), "静态路径应保持不变")
+	assertEqual(t, "/task/{task_id}", endpointOf("/task/RASA_123"), "任务路径应归一化")
+	assertEqual(t, "/task/schedule/{task_id}", endpointOf("/task/schedule/RASA_123"), "定时任务路径应归一化")
+	assertEqual(t, "/user/alias/batch", endpointOf("/user/alias/batch"), "静态路径优先于参数路径")
+	assertEqual(t, "/user/list", endpointOf("/user/list?page=1&size=100"), "应去掉查询参数")
+}
+
+func TestHealthMonitor_Disabled(t *testing.T) {
+	config := NewDefaultConfig()
+	hm := NewHealthMonitor(config, nil, nil)
+
+	hm.Record("/push/single/cid", time.Millisecond, errors.New("failed"))
+	snapshot := hm.Snapshot()
+
+	assertFalse(t, snapshot.Enabled, "未开启时Enabled应为false")
+	assertEqual(t, 0, len(snapshot.Endpoints), "未开启时不应记录接口数据")
+	assertTrue(t, snapshot.Healthy, "没有数据时应视为健康")
+}
+
+func TestHealthMonitor_ErrorRate(t *testing.T) {
+	config := NewDefaultConfig()
+	config.OpenCheckHealthDataSwitch = true
+	hm := NewHealthMonitor(config, func() TokenHealth { return TokenHealth{Valid: true} }, nil)
+
+	hm.Record("/task/a", 10*time.Millisecond, nil)
+	hm.Record("/task/b", 30*time.Millisecond, errors.New("failed"))
+	hm.rotate()
+	hm.Record("/task/c", 20*time.Millisecond, errors.New("failed"))
+
+	snapshot := hm.Snapshot()
+	endpoint := snapshot.Endpoints["/task/{task_id}"]
+
+	assertEqual(t, int64(3), endpoint.Requests, "应合并两个统计窗口的请求数")
+	assertEqual(t, int64(2), endpoint.Errors, "应合并两个统计窗口的错误数")
+	assertEqual(t, 20*time.Millisecond, endpoint.AvgLatency, "平均延迟应正确")
+	assertEqual(t, "failed", endpoint.LastError, "应记录最后一次错误")
+	assertFalse(t, snapshot.Healthy, "错误率超过阈值时应不健康")
+
+	hm.rotate()
+	hm.rotate()
+	assertTrue(t, hm.Snapshot().Healthy, "窗口滚动后旧数据应被丢弃")
+}
+
+func TestClient_HealthHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	client := createHealthTestClient(server.URL)
+	defer client.healthMonitor.Stop()
+
+	_, err := client.DoRequest("GET", "/user/count", nil)
+	assertNoError(t, err, "请求不应该返回错误")
+
+	recorder := httptest.NewRecorder()
+	client.HealthHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/healthz", nil))
+
+	assertEqual(t, http.StatusOK, recorder.Code, "健康时应返回200")
+
+	var snapshot HealthSnapshot
+	assertNoError(t, json.Unmarshal(recorder.Body.Bytes(), &snapshot), "应返回JSON快照")
+	assertTrue(t, snapshot.Token.Valid, "token应有效")
+	assertEqual(t, int64(1), snapshot.Endpoints["/user/count"].Requests, "应记录接口请求")
+}
+
+func TestClient_HealthAuthFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":20001,"msg":"appkey not exist"}`))
+	}))
+	defer server.Close()
+
+	client := createHealthTestClient(server.URL)
+	defer client.healthMonitor.Stop()
+	client.GetTokenManager().ClearToken()
+
+	_, err := client.GetToken()
+	assertError(t, err, "鉴权应该失败")
+
+	recorder := httptest.NewRecorder()
+	client.HealthHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/healthz", nil))
+
+	assertEqual(t, http.StatusServiceUnavailable, recorder.Code, "鉴权失败时应返回503")
+}
@@ -0,0 +1,179 @@
+package getui
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxResponseBodySize 默认的响应体最大字节数
+const DefaultMaxResponseBodySize = 10 << 20
+
+// maxErrorBodySnippet HTTPError中保留的响应体最大字节数
+const maxErrorBodySnippet = 512
+
+// HTTPError 响应不是个推的标准响应格式时返回的错误，如网关返回的5xx HTML页面。
+// 可通过errors.Is(err, ErrServerError)等按状态码判断错误类别
+type HTTPError struct {
+	StatusCode int
+	Header     http.Header
+	Body       string        // 截断后的响应体
+	RequestID  string        // 响应头中的请求ID，便于排查
+	RetryAfter time.Duration // 429或503响应中Retry-After要求的等待时间
+	Err        error         // 错误类别哨兵
+}
+
+func (e *HTTPError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "http error: status=%d", e.StatusCode)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", request_id=%s", e.RequestID)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ", cause=%v", e.Err)
+	}
+	if e.Body != "" {
+		fmt.Fprintf(&b, ", body=%s", e.Body)
+	}
+	return b.String()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// requestIDHeaders 可能携带请求ID的响应头
+var requestIDHeaders = []string{"X-Request-Id", "X-Trace-Id", "X-Getui-Request-Id"}
+
+// readResult 读取并解析响应。响应体超过maxSize、非2xx状态码且不是个推的错误响应、
+// 或2xx状态码但无法解析时返回HTTPError
+func readResult(resp *http.Response, maxSize int, now time.Time) (*ApiResult, error) {
+	body, err := readBody(resp, maxSize)
+	if err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, newHTTPError(resp, body, now, err)
+		}
+		return nil, &NetworkError{Message: "failed to read response", Cause: err}
+	}
+
+	var result ApiResult
+	decodeErr := json.Unmarshal(body, &result)
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	switch {
+	case decodeErr == nil && success:
+		return &result, nil
+	case decodeErr == nil && result.Code != 0:
+		// 非2xx状态码但返回了个推的错误码，按返回码处理
+		return &result, nil
+	case success:
+		return nil, newHTTPError(resp, body, now, fmt.Errorf("%w: %v", ErrInvalidResponse, decodeErr))
+	default:
+		return nil, newHTTPError(resp, body, now, statusSentinel(resp.StatusCode))
+	}
+}
+
+// readBody 读取响应体，处理gzip压缩并限制解压后的大小
+func readBody(resp *http.Response, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseBodySize
+	}
+
+	reader := io.Reader(resp.Body)
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") && !resp.Uncompressed {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSize {
+		return body[:maxSize], fmt.Errorf("%w: limit %d bytes", ErrResponseTooLarge, maxSize)
+	}
+	return body, nil
+}
+
+// newHTTPError 根据响应生成HTTPError
+func newHTTPError(resp *http.Response, body []byte, now time.Time, err error) *HTTPError {
+	httpErr := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       truncateBody(body),
+		Err:        err,
+	}
+	for _, name := range requestIDHeaders {
+		if id := resp.Header.Get(name); id != "" {
+			httpErr.RequestID = id
+			break
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		httpErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	}
+	return httpErr
+}
+
+// statusSentinel 根据HTTP状态码获取错误类别哨兵
+func statusSentinel(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	default:
+		return ErrHTTPRequestFailed
+	}
+}
+
+// parseRetryAfter 解析Retry-After头，支持秒数和HTTP日期两种格式，无法解析时返回0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// truncateBody 截断响应体用于错误信息，保证结果是合法的UTF-8
+func truncateBody(body []byte) string {
+	if len(body) <= maxErrorBodySnippet {
+		return strings.ToValidUTF8(string(body), "")
+	}
+	cut := maxErrorBodySnippet
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return strings.ToValidUTF8(string(body[:cut]), "") + "..."
+}
+
+// retryAfter 获取错误中服务端要求的重试等待时间
+func retryAfter(err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.RetryAfter
+	}
+	return 0
+}
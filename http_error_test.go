@@ -0,0 +1,121 @@
+package getui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>" + strings.Repeat("bad gateway ", 100) + "</html>"))
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 1)
+	_, err := client.DoRequest("GET", "/user/count", nil)
+
+	var httpErr *HTTPError
+	assertTrue(t, errors.As(err, &httpErr), "非JSON响应应返回HTTPError")
+	assertEqual(t, http.StatusBadGateway, httpErr.StatusCode, "应保留状态码")
+	assertEqual(t, "req_123", httpErr.RequestID, "应保留请求ID")
+	assertTrue(t, strings.HasPrefix(httpErr.Body, "<html>bad gateway"), "应保留响应体片段")
+	assertTrue(t, len(httpErr.Body) <= maxErrorBodySnippet+3, "响应体片段应被截断")
+	assertTrue(t, errors.Is(err, ErrServerError), "5xx应属于服务端异常")
+	assertTrue(t, IsRetryable(err), "5xx应可以重试")
+}
+
+func TestDoRequest_ErrorEnvelopeWithStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":20001,"msg":"param error"}`))
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 1)
+	result, err := client.DoRequest("GET", "/user/count", nil)
+	assertNoError(t, err, "非2xx但返回了个推错误码时应按返回码处理")
+	assertEqual(t, 20001, result.Code, "应返回个推错误码")
+}
+
+func TestDoRequest_GzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, "gzip", r.Header.Get("Accept-Encoding"), "请求应声明支持gzip")
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(`{"code":0,"msg":"success","data":{"user_count":3}}`))
+		gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 1)
+	count, err := client.UserAPI.GetUserCount()
+	assertNoError(t, err, "gzip响应应能解析")
+	assertEqual(t, int64(3), count, "应解压后解析数据")
+}
+
+func TestDoRequest_MaxResponseBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"msg":"success","data":"` + strings.Repeat("x", 1024) + `"}`))
+	}))
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client, err := NewClientWithOptions(config, WithMaxResponseBodySize(512))
+	assertNoError(t, err, "创建客户端不应返回错误")
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+	_, err = client.DoRequest("GET", "/user/count", nil)
+	assertTrue(t, errors.Is(err, ErrResponseTooLarge), "响应体超过限制时应返回ErrResponseTooLarge")
+	assertTrue(t, errors.Is(err, ErrInvalidResponse), "ErrResponseTooLarge应属于ErrInvalidResponse")
+	assertFalse(t, IsRetryable(err), "响应体过大不应重试")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assertEqual(t, 120*time.Second, parseRetryAfter("120", now), "应解析秒数")
+	assertEqual(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now), "应解析HTTP日期")
+	assertEqual(t, time.Duration(0), parseRetryAfter(now.Add(-time.Second).Format(http.TimeFormat), now), "过去的时间应返回0")
+	assertEqual(t, time.Duration(0), parseRetryAfter("soon", now), "无法解析时应返回0")
+}
+
+func TestDoRequest_RetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	// Retry-After超过MaxBackoff时不重试
+	client := createRetryTestClient(server.URL, 3)
+	_, err := client.DoRequest("GET", "/user/count", nil)
+	var httpErr *HTTPError
+	assertTrue(t, errors.As(err, &httpErr), "应返回HTTPError")
+	assertEqual(t, time.Second, httpErr.RetryAfter, "应解析Retry-After")
+	assertEqual(t, int32(1), atomic.LoadInt32(&attempts), "Retry-After超过MaxBackoff时不应重试")
+
+	// Retry-After在MaxBackoff以内时等待后重试
+	atomic.StoreInt32(&attempts, 0)
+	client.GetConfig().RetryPolicy.MaxBackoff = 2 * time.Second
+	start := time.Now()
+	result, err := client.DoRequest("GET", "/user/count", nil)
+	assertNoError(t, err, "等待后重试应该成功")
+	assertTrue(t, result.IsSuccess(), "重试结果应该成功")
+	assertTrue(t, time.Since(start) >= time.Second, "应按Retry-After等待")
+}
@@ -0,0 +1,340 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 环境变量前缀
+const (
+	DefaultEnvPrefix = "GETUI_"
+	TestEnvPrefix    = "GETUI_TEST_"
+)
+
+// ConfigLoader 配置加载器，按以下优先级从低到高合并配置：
+// 默认配置、Files中的文件（按顺序，后面的覆盖前面的）、进程环境变量。
+//
+// 以.json结尾的文件按JSON解析，字段名同Config的json标签，未知字段视为错误；.env、.env.*和没有扩展名的文件
+// 按.env格式解析；其他格式（如YAML）返回ErrUnsupportedConfigFormat。
+// 环境变量名为前缀加大写的json标签，嵌套字段用下划线连接，如GETUI_SOCKET_TIMEOUT、GETUI_PROXY_CONFIG_HOST。
+// 时间字段使用"30s"格式（JSON中也可以是纳秒整数），列表用逗号分隔，
+// URIToSocketTimeoutMap使用"/push/all=60000,/task/{task_id}=3000"格式
+type ConfigLoader struct {
+	EnvPrefix        string   // 环境变量前缀，为空时使用DefaultEnvPrefix
+	Files            []string // 配置文件
+	IgnoreEnv        bool     // 是否忽略进程环境变量
+	SkipMissingFiles bool     // 文件不存在时是否跳过
+}
+
+// NewConfigLoader 创建配置加载器
+func NewConfigLoader(files ...string) *ConfigLoader {
+	return &ConfigLoader{EnvPrefix: DefaultEnvPrefix, Files: files}
+}
+
+// LoadConfig 从配置文件和GETUI_前缀的环境变量加载配置
+func LoadConfig(files ...string) (*Config, error) {
+	return NewConfigLoader(files...).Load()
+}
+
+// Load 加载配置。文件无法读取时返回对应错误，值格式错误时返回包含全部问题的ConfigErrors
+func (l *ConfigLoader) Load() (*Config, error) {
+	prefix := l.EnvPrefix
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	config := NewDefaultConfig()
+	var errs ConfigErrors
+	for _, file := range l.Files {
+		format, err := configFileFormat(file)
+		if err != nil {
+			return nil, err
+		}
+		if format == configFormatJSON {
+			err = loadJSONFile(config, file, &errs)
+		} else {
+			var values map[string]string
+			if values, err = readEnvFile(file); err == nil {
+				applyEnv(config, prefix, func(key string) (string, bool) {
+					value, ok := values[key]
+					return value, ok
+				}, file, &errs)
+			}
+		}
+		if err != nil {
+			if l.SkipMissingFiles && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+	}
+
+	if !l.IgnoreEnv {
+		applyEnv(config, prefix, os.LookupEnv, "environment", &errs)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return config, nil
+}
+
+// 配置文件格式
+const (
+	configFormatJSON = "json"
+	configFormatEnv  = "env"
+)
+
+// configFileFormat 根据文件名判断格式：.json为JSON，.env、.env.*或没有扩展名的文件为.env格式，
+// 其余扩展名（如.yaml）返回ErrUnsupportedConfigFormat，避免无法识别的文件被静默忽略
+func configFileFormat(file string) (string, error) {
+	base := filepath.Base(file)
+	ext := strings.ToLower(filepath.Ext(base))
+	switch {
+	case ext == ".json":
+		return configFormatJSON, nil
+	case ext == ".env", ext == "", strings.HasPrefix(base, ".env"):
+		return configFormatEnv, nil
+	default:
+		return "", fmt.Errorf("%w: %s (supported: .json, .env)", ErrUnsupportedConfigFormat, file)
+	}
+}
+
+// configField 可加载的配置字段
+type configField struct {
+	name  string // json标签，嵌套字段用下划线连接，如proxy_config_host
+	index []int
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configFields 获取可加载的配置字段，跳过json标签为"-"的字段
+func configFields(t reflect.Type, prefix string, index []int) []configField {
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
+			fields = append(fields, configFields(f.Type.Elem(), prefix+name+"_", fieldIndex)...)
+			continue
+		}
+		fields = append(fields, configField{name: prefix + name, index: fieldIndex})
+	}
+	return fields
+}
+
+// jsonFieldName 获取字段的json名称，不参与序列化时返回空字符串
+func jsonFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name
+}
+
+// applyEnv 按前缀从lookup读取环境变量并写入配置
+func applyEnv(config *Config, prefix string, lookup func(string) (string, bool), source string, errs *ConfigErrors) {
+	for _, field := range configFields(reflect.TypeOf(*config), "", nil) {
+		key := prefix + strings.ToUpper(field.name)
+		value, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setFieldString(fieldByIndex(config, field.index), strings.TrimSpace(value)); err != nil {
+			*errs = append(*errs, &ConfigError{Field: key, Message: fmt.Sprintf("%v (from %s)", err, source)})
+		}
+	}
+}
+
+// fieldByIndex 获取配置字段，沿途为nil的结构体指针会被初始化
+func fieldByIndex(config *Config, index []int) reflect.Value {
+	v := reflect.ValueOf(config).Elem()
+	for i, idx := range index {
+		if i > 0 {
+			v = nestedValue(config, v)
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// nestedValue 获取结构体指针指向的值，为nil时初始化，重试策略以当前默认策略为基础
+func nestedValue(config *Config, ptr reflect.Value) reflect.Value {
+	if ptr.IsNil() {
+		if ptr.Type() == reflect.TypeOf(config.RetryPolicy) {
+			policy := *config.GetRetryPolicy()
+			ptr.Set(reflect.ValueOf(&policy))
+		} else {
+			ptr.Set(reflect.New(ptr.Type().Elem()))
+		}
+	}
+	return ptr.Elem()
+}
+
+// setFieldString 将字符串解析后写入字段
+func setFieldString(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, expected a value like \"30s\"", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(s)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setFieldString(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(s) {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, expected key=value", item)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setFieldString(elem, strings.TrimSpace(value)); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// splitList 按逗号拆分列表，忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadJSONFile 读取JSON配置文件并写入配置
+func loadJSONFile(config *Config, filename string, errs *ConfigErrors) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := applyJSON(config, reflect.ValueOf(config).Elem(), data, "", filename, errs); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// applyJSON 将JSON对象写入结构体，字段错误记录到errs，JSON格式错误时返回error
+func applyJSON(config *Config, v reflect.Value, data []byte, path, source string, errs *ConfigErrors) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		if name := jsonFieldName(v.Type().Field(i)); name != "" {
+			fields[name] = v.Field(i)
+		}
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := raw[key]
+		field, ok := fields[key]
+		if !ok {
+			*errs = append(*errs, &ConfigError{Field: path + key, Message: fmt.Sprintf("unknown field (from %s)", source)})
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+			if string(value) == "null" {
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+			if err := applyJSON(config, nestedValue(config, field), value, path+key+".", source, errs); err != nil {
+				*errs = append(*errs, &ConfigError{Field: path + key, Message: fmt.Sprintf("%v (from %s)", err, source)})
+			}
+			continue
+		}
+
+		if err := setFieldJSON(field, value); err != nil {
+			*errs = append(*errs, &ConfigError{Field: path + key, Message: fmt.Sprintf("%v (from %s)", err, source)})
+		}
+	}
+	return nil
+}
+
+// setFieldJSON 将JSON值写入字段，时间字段支持"30s"格式的字符串
+func setFieldJSON(v reflect.Value, value json.RawMessage) error {
+	if v.Type() == durationType && len(value) > 0 && value[0] == '"' {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return err
+		}
+		return setFieldString(v, s)
+	}
+
+	if err := json.Unmarshal(value, v.Addr().Interface()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("invalid value %s, expected %s", value, typeErr.Type)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package getui
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	return path
+}
+
+func TestConfigLoader_Precedence(t *testing.T) {
+	jsonFile := writeTestFile(t, "getui.json", `{
+		"app_id": "json_app_id",
+		"app_key": "json_app_key",
+		"master_secret": "json_master_secret",
+		"domains": ["https://api2.getui.com/v2"],
+		"socket_timeout": 5000,
+		"token_expire_margin": "30m",
+		"check_health_interval": 60000000000,
+		"retry_policy": {"max_attempts": 3},
+		"proxy_config": {"host": "proxy.internal", "port": 8080, "no_proxy": ["localhost"]},
+		"uri_to_socket_timeout_map": {"/push/all": 60000}
+	}`)
+	envFile := writeTestFile(t, "getui.env", `
+APP_TEST_APP_KEY=env_file_app_key
+APP_TEST_SOCKET_TIMEOUT=6000
+APP_TEST_PROXY_CONFIG_SCHEME=socks5
+`)
+	t.Setenv("APP_TEST_SOCKET_TIMEOUT", "7000")
+	t.Setenv("APP_TEST_TOKEN_AUTO_REFRESH", "true")
+	t.Setenv("APP_TEST_URI_TO_SOCKET_TIMEOUT_MAP", "/task/{task_id}=3000, /user/list=2000")
+
+	loader := &ConfigLoader{EnvPrefix: "APP_TEST_", Files: []string{jsonFile, envFile}}
+	config, err := loader.Load()
+	assertNoError(t, err, "加载配置不应返回错误")
+
+	assertEqual(t, "json_app_id", config.AppID, "应读取JSON文件")
+	assertEqual(t, "env_file_app_key", config.AppKey, ".env文件应覆盖JSON文件")
+	assertEqual(t, 7000, config.SocketTimeout, "环境变量优先级最高")
+	assertEqual(t, "https://restapi.getui.com/v2", config.Domain, "未配置的字段使用默认值")
+	assertEqual(t, 30*time.Minute, config.TokenExpireMargin, "应解析时间字符串")
+	assertEqual(t, time.Minute, config.CheckHealthInterval, "应解析纳秒整数")
+	assertTrue(t, config.TokenAutoRefresh, "应解析布尔值")
+	assertEqual(t, 3, config.RetryPolicy.MaxAttempts, "应读取嵌套字段")
+	assertEqual(t, 100*time.Millisecond, config.RetryPolicy.InitialBackoff, "未配置的重试参数使用默认值")
+	assertEqual(t, "proxy.internal", config.ProxyConfig.Host, "应读取代理配置")
+	assertEqual(t, ProxySchemeSOCKS5, config.ProxyConfig.Scheme, "应合并不同来源的代理配置")
+	assertEqual(t, 2000, config.URIToSocketTimeoutMap["/user/list"], "应解析环境变量中的映射")
+	_, exists := config.URIToSocketTimeoutMap["/push/all"]
+	assertFalse(t, exists, "高优先级的映射整体覆盖低优先级")
+}
+
+func TestConfigLoader_MalformedValues(t *testing.T) {
+	jsonFile := writeTestFile(t, "getui.json", `{"socket_timeout": "fast", "unknown_field": 1, "proxy_config": {"port": "80"}}`)
+	t.Setenv("APP_TEST_TOKEN_EXPIRE_MARGIN", "10")
+	t.Setenv("APP_TEST_TRUST_SSL", "maybe")
+
+	loader := &ConfigLoader{EnvPrefix: "APP_TEST_", Files: []string{jsonFile}}
+	_, err := loader.Load()
+
+	var configErrs ConfigErrors
+	assertTrue(t, errors.As(err, &configErrs), "值格式错误时应返回ConfigErrors")
+	fields := make(map[string]bool)
+	for _, e := range configErrs {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"socket_timeout", "unknown_field", "proxy_config.port", "APP_TEST_TOKEN_EXPIRE_MARGIN", "APP_TEST_TRUST_SSL"} {
+		assertTrue(t, fields[field], "应包含字段错误: "+field)
+	}
+}
+
+func TestConfigLoader_Files(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assertTrue(t, errors.Is(err, os.ErrNotExist), "文件不存在时应返回错误")
+
+	loader := &ConfigLoader{Files: []string{filepath.Join(t.TempDir(), "missing.env")}, SkipMissingFiles: true, IgnoreEnv: true}
+	config, err := loader.Load()
+	assertNoError(t, err, "允许跳过时文件不存在不应返回错误")
+	assertEqual(t, NewDefaultConfig().Domain, config.Domain, "应返回默认配置")
+
+	_, err = LoadConfig(writeTestFile(t, "broken.json", `{"app_id": `))
+	assertError(t, err, "JSON格式错误时应返回错误")
+}
+
+func TestLoadConfigFromEnvFileOrDefault_Malformed(t *testing.T) {
+	envFile := writeTestFile(t, "test.env", "GETUI_TEST_APP_ID=app\nGETUI_TEST_SOCKET_TIMEOUT=abc\n")
+
+	_, err := LoadConfigFromEnvFile(envFile)
+	assertError(t, err, "值格式错误时应返回错误")
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	config := LoadConfigFromEnvFileOrDefault(envFile)
+	assertEqual(t, "", config.AppID, "值格式错误时应返回默认配置")
+	assertTrue(t, strings.Contains(buf.String(), "GETUI_TEST_SOCKET_TIMEOUT"), "值格式错误时应输出日志")
+}
+
+func TestConfigLoader_UnsupportedFormat(t *testing.T) {
+	_, err := LoadConfig(writeTestFile(t, "config.yaml", "app_id: app\n"))
+	assertTrue(t, errors.Is(err, ErrUnsupportedConfigFormat), "不支持的文件格式应返回错误")
+
+	for _, name := range []string{"app.env", ".env.local", "getui"} {
+		config, err := LoadConfigFromEnvFile(writeTestFile(t, name, "GETUI_TEST_APP_ID=app\n"))
+		assertNoError(t, err, name+"应按.env格式解析")
+		assertEqual(t, "app", config.AppID, name+"应读取配置")
+	}
+}
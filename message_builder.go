@@ -0,0 +1,298 @@
+package getui
+
+import (
+	"fmt"
+)
+
+// 通知点击后的动作
+const (
+	ClickTypeStartApp      = "startapp"       // 打开应用首页
+	ClickTypeURL           = "url"            // 打开网页
+	ClickTypeIntent        = "intent"         // 打开应用内页面
+	ClickTypePayload       = "payload"        // 自定义消息内容启动应用
+	ClickTypePayloadCustom = "payload_custom" // 自定义消息内容不启动应用
+	ClickTypeNone          = "none"           // 纯通知，无后续动作
+)
+
+// 鸿蒙通知点击后的动作
+const (
+	HarmonyClickTypeStartApp = "startapp" // 打开应用首页
+	HarmonyClickTypeWant     = "want"     // 打开应用内页面
+)
+
+// Platform 厂商通道平台
+type Platform string
+
+const (
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	PlatformHarmony Platform = "harmony"
+)
+
+// iosMultimediaImage iOS多媒体类型：图片
+const iosMultimediaImage = 1
+
+// MessageBuilder 根据一条通知生成个推通道的PushMessage以及iOS、Android厂商、鸿蒙通道的PushChannel，
+// 标题、内容、点击动作等只需设置一次：
+//
+//	message, channel, err := getui.NewMessage("标题", "内容").
+//		ClickURL("https://www.getui.com").
+//		Badge(1).
+//		OverrideIOS(func(ios *getui.IOSDTO) { ios.APNS.Sound = "ring.caf" }).
+//		Build()
+type MessageBuilder struct {
+	title     string
+	body      string
+	clickType string
+	url       string
+	intent    string
+	want      string
+	payload   string
+	badge     int
+	sound     string
+	image     string
+	platforms []Platform
+
+	iosOverrides     []func(*IOSDTO)
+	androidOverrides []func(*AndroidDTO)
+	harmonyOverrides []func(*HarmonyDTO)
+}
+
+// NewMessage 创建通知消息构建器，默认点击后打开应用首页，并下发到全部厂商通道
+func NewMessage(title, body string) *MessageBuilder {
+	return &MessageBuilder{
+		title:     title,
+		body:      body,
+		clickType: ClickTypeStartApp,
+		platforms: []Platform{PlatformIOS, PlatformAndroid, PlatformHarmony},
+	}
+}
+
+// ClickURL 点击后打开网页
+func (b *MessageBuilder) ClickURL(url string) *MessageBuilder {
+	b.clickType = ClickTypeURL
+	b.url = url
+	return b
+}
+
+// ClickIntent 点击后打开应用内页面，鸿蒙通道的页面通过HarmonyWant设置
+func (b *MessageBuilder) ClickIntent(intent string) *MessageBuilder {
+	b.clickType = ClickTypeIntent
+	b.intent = intent
+	return b
+}
+
+// ClickPayload 点击后启动应用并传递payload
+func (b *MessageBuilder) ClickPayload(payload string) *MessageBuilder {
+	b.clickType = ClickTypePayload
+	b.payload = payload
+	return b
+}
+
+// ClickPayloadCustom 点击后不启动应用，由应用自行处理payload
+func (b *MessageBuilder) ClickPayloadCustom(payload string) *MessageBuilder {
+	b.clickType = ClickTypePayloadCustom
+	b.payload = payload
+	return b
+}
+
+// ClickStartApp 点击后打开应用首页
+func (b *MessageBuilder) ClickStartApp() *MessageBuilder {
+	b.clickType = ClickTypeStartApp
+	return b
+}
+
+// ClickNone 纯通知，点击后无后续动作
+func (b *MessageBuilder) ClickNone() *MessageBuilder {
+	b.clickType = ClickTypeNone
+	return b
+}
+
+// HarmonyWant 设置鸿蒙通道点击后打开的页面，未设置时打开应用首页
+func (b *MessageBuilder) HarmonyWant(want string) *MessageBuilder {
+	b.want = want
+	return b
+}
+
+// Payload 设置透传给应用的数据，iOS通道放在custom_data的payload字段中
+func (b *MessageBuilder) Payload(payload string) *MessageBuilder {
+	b.payload = payload
+	return b
+}
+
+// Badge 设置角标
+func (b *MessageBuilder) Badge(badge int) *MessageBuilder {
+	b.badge = badge
+	return b
+}
+
+// Sound 设置iOS通知铃声，如default
+func (b *MessageBuilder) Sound(sound string) *MessageBuilder {
+	b.sound = sound
+	return b
+}
+
+// Image 设置通知大图，iOS通道作为多媒体资源下发
+func (b *MessageBuilder) Image(url string) *MessageBuilder {
+	b.image = url
+	return b
+}
+
+// Platforms 设置需要下发的厂商通道，不包含的平台只走个推通道
+func (b *MessageBuilder) Platforms(platforms ...Platform) *MessageBuilder {
+	b.platforms = platforms
+	return b
+}
+
+// OverrideIOS 在生成的iOS通道参数上做修改，可多次调用，按顺序执行
+func (b *MessageBuilder) OverrideIOS(fn func(*IOSDTO)) *MessageBuilder {
+	b.iosOverrides = append(b.iosOverrides, fn)
+	return b
+}
+
+// OverrideAndroid 在生成的Android厂商通道参数上做修改，可多次调用，按顺序执行
+func (b *MessageBuilder) OverrideAndroid(fn func(*AndroidDTO)) *MessageBuilder {
+	b.androidOverrides = append(b.androidOverrides, fn)
+	return b
+}
+
+// OverrideHarmony 在生成的鸿蒙通道参数上做修改，可多次调用，按顺序执行
+func (b *MessageBuilder) OverrideHarmony(fn func(*HarmonyDTO)) *MessageBuilder {
+	b.harmonyOverrides = append(b.harmonyOverrides, fn)
+	return b
+}
+
+// Build 生成个推通道消息和厂商通道参数
+func (b *MessageBuilder) Build() (*PushMessage, *PushChannel, error) {
+	if err := b.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	message := &PushMessage{
+		Notification: &Notification{
+			Title:     b.title,
+			Body:      b.body,
+			ClickType: b.clickType,
+			URL:       b.url,
+			Intent:    b.intent,
+			Payload:   b.payload,
+			Badge:     b.badge,
+			BigImage:  b.image,
+		},
+	}
+
+	channel := &PushChannel{}
+	for _, platform := range b.platforms {
+		switch platform {
+		case PlatformIOS:
+			channel.IOS = b.buildIOS()
+		case PlatformAndroid:
+			channel.Android = b.buildAndroid()
+		case PlatformHarmony:
+			channel.Harmony = b.buildHarmony()
+		default:
+			return nil, nil, fmt.Errorf("%w: unknown platform %q", ErrInvalidPushMessage, platform)
+		}
+	}
+	if channel.IOS == nil && channel.Android == nil && channel.Harmony == nil {
+		channel = nil
+	}
+
+	return message, channel, nil
+}
+
+// BuildPushDTO 生成推送请求
+func (b *MessageBuilder) BuildPushDTO(audience *Audience) (*PushDTO, error) {
+	message, channel, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &PushDTO{Audience: audience, PushMessage: message, PushChannel: channel}, nil
+}
+
+// validate 校验标题、内容和点击动作需要的参数
+func (b *MessageBuilder) validate() error {
+	if b.title == "" || b.body == "" {
+		return fmt.Errorf("%w: title and body are required", ErrInvalidPushMessage)
+	}
+
+	switch b.clickType {
+	case ClickTypeURL:
+		if b.url == "" {
+			return fmt.Errorf("%w: url is required for click_type url", ErrInvalidPushMessage)
+		}
+	case ClickTypeIntent:
+		if b.intent == "" {
+			return fmt.Errorf("%w: intent is required for click_type intent", ErrInvalidPushMessage)
+		}
+	case ClickTypePayload, ClickTypePayloadCustom:
+		if b.payload == "" {
+			return fmt.Errorf("%w: payload is required for click_type %s", ErrInvalidPushMessage, b.clickType)
+		}
+	}
+	return nil
+}
+
+// buildIOS 生成iOS通道参数
+func (b *MessageBuilder) buildIOS() *IOSDTO {
+	ios := &IOSDTO{
+		Type: "notify",
+		APNS: &APNS{
+			Alert: &Alert{Title: b.title, Body: b.body},
+			Badge: b.badge,
+			Sound: b.sound,
+		},
+	}
+	if b.payload != "" {
+		ios.APNS.CustomData = map[string]string{"payload": b.payload}
+	}
+	if b.image != "" {
+		ios.APNS.MutableContent = 1
+		ios.Multimedia = []*Multimedia{{URL: b.image, Type: iosMultimediaImage}}
+	}
+
+	for _, fn := range b.iosOverrides {
+		fn(ios)
+	}
+	return ios
+}
+
+// buildAndroid 生成Android厂商通道参数，厂商通道不支持的点击动作使用打开应用首页
+func (b *MessageBuilder) buildAndroid() *AndroidDTO {
+	notification := &ThirdNotification{
+		Title:     b.title,
+		Body:      b.body,
+		ClickType: b.clickType,
+		URL:       b.url,
+		Intent:    b.intent,
+		Payload:   b.payload,
+	}
+	if b.clickType == ClickTypePayloadCustom || b.clickType == ClickTypeNone {
+		notification.ClickType = ClickTypeStartApp
+	}
+
+	android := &AndroidDTO{UPS: &UPS{Notification: notification}}
+	for _, fn := range b.androidOverrides {
+		fn(android)
+	}
+	return android
+}
+
+// buildHarmony 生成鸿蒙通道参数，设置了want时打开指定页面，否则打开应用首页
+func (b *MessageBuilder) buildHarmony() *HarmonyDTO {
+	notification := &HarmonyNotification{
+		Title:     b.title,
+		Body:      b.body,
+		ClickType: HarmonyClickTypeStartApp,
+	}
+	if b.want != "" {
+		notification.ClickType = HarmonyClickTypeWant
+		notification.Want = b.want
+	}
+
+	harmony := &HarmonyDTO{Notification: notification}
+	for _, fn := range b.harmonyOverrides {
+		fn(harmony)
+	}
+	return harmony
+}
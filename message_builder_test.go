@@ -0,0 +1,116 @@
+package getui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMessageBuilder_FanOut(t *testing.T) {
+	message, channel, err := NewMessage("标题", "内容").
+		ClickURL("https://www.getui.com").
+		Payload(`{"id":1}`).
+		Badge(2).
+		Sound("default").
+		Image("https://www.getui.com/a.png").
+		Build()
+	assertNoError(t, err, "构建消息不应返回错误")
+
+	n := message.Notification
+	assertEqual(t, "标题", n.Title, "个推通道标题")
+	assertEqual(t, ClickTypeURL, n.ClickType, "个推通道点击动作")
+	assertEqual(t, "https://www.getui.com", n.URL, "个推通道URL")
+	assertEqual(t, "https://www.getui.com/a.png", n.BigImage, "个推通道大图")
+
+	ios := channel.IOS
+	assertEqual(t, "notify", ios.Type, "iOS消息类型")
+	assertEqual(t, "内容", ios.APNS.Alert.Body, "iOS内容")
+	assertEqual(t, 2, ios.APNS.Badge, "iOS角标")
+	assertEqual(t, "default", ios.APNS.Sound, "iOS铃声")
+	assertEqual(t, `{"id":1}`, ios.APNS.CustomData["payload"], "iOS payload")
+	assertEqual(t, 1, ios.APNS.MutableContent, "iOS多媒体需要mutable_content")
+	assertEqual(t, "https://www.getui.com/a.png", ios.Multimedia[0].URL, "iOS多媒体")
+
+	ups := channel.Android.UPS.Notification
+	assertEqual(t, "标题", ups.Title, "Android厂商通道标题")
+	assertEqual(t, ClickTypeURL, ups.ClickType, "Android厂商通道点击动作")
+	assertEqual(t, "https://www.getui.com", ups.URL, "Android厂商通道URL")
+
+	harmony := channel.Harmony.Notification
+	assertEqual(t, "内容", harmony.Body, "鸿蒙内容")
+	assertEqual(t, HarmonyClickTypeStartApp, harmony.ClickType, "鸿蒙不支持URL时打开应用首页")
+}
+
+func TestMessageBuilder_Overrides(t *testing.T) {
+	pushDTO, err := NewMessage("标题", "内容").
+		ClickIntent("intent://detail").
+		HarmonyWant(`{"bundleName":"com.getui.demo","abilityName":"DetailAbility"}`).
+		Platforms(PlatformAndroid, PlatformHarmony).
+		OverrideAndroid(func(android *AndroidDTO) {
+			android.UPS.Notification.Title = "Android标题"
+			android.UPS.Options = map[string]string{"HW": "/message/android/category=WORK"}
+		}).
+		OverrideHarmony(func(harmony *HarmonyDTO) {
+			harmony.Notification.Category = "WORK"
+		}).
+		BuildPushDTO(ByCIDs("cid_1"))
+	assertNoError(t, err, "构建推送请求不应返回错误")
+
+	assertEqual(t, AudienceKindCID, pushDTO.Audience.Kind(), "应设置受众")
+	assertEqual(t, "标题", pushDTO.PushMessage.Notification.Title, "覆盖不应影响个推通道")
+	assertNil(t, pushDTO.PushChannel.IOS, "未选择的平台不应生成参数")
+	assertEqual(t, "Android标题", pushDTO.PushChannel.Android.UPS.Notification.Title, "应应用Android覆盖")
+	assertEqual(t, "WORK", pushDTO.PushChannel.Harmony.Notification.Category, "应应用鸿蒙覆盖")
+	assertEqual(t, "intent://detail", pushDTO.PushChannel.Android.UPS.Notification.Intent, "Android应使用intent")
+	assertEqual(t, HarmonyClickTypeWant, pushDTO.PushChannel.Harmony.Notification.ClickType, "鸿蒙应使用want打开页面")
+	assertEqual(t, `{"bundleName":"com.getui.demo","abilityName":"DetailAbility"}`, pushDTO.PushChannel.Harmony.Notification.Want, "鸿蒙应使用设置的want")
+
+	_, channel, err := NewMessage("标题", "内容").ClickIntent("intent://detail").Build()
+	assertNoError(t, err, "构建消息不应返回错误")
+	assertEqual(t, HarmonyClickTypeStartApp, channel.Harmony.Notification.ClickType, "未设置want时鸿蒙应打开应用首页")
+	assertEqual(t, "", channel.Harmony.Notification.Want, "intent不应作为want")
+
+	_, channel, _ = NewMessage("标题", "内容").Platforms().Build()
+	assertNil(t, channel, "不选择平台时不应生成厂商通道参数")
+}
+
+func TestMessageBuilder_UnsupportedClickType(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *MessageBuilder
+		expect  string
+	}{
+		{"纯通知", NewMessage("标题", "内容").ClickNone(), ClickTypeNone},
+		{"不启动应用", NewMessage("标题", "内容").ClickPayloadCustom(`{"id":1}`), ClickTypePayloadCustom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, channel, err := tt.builder.Build()
+			assertNoError(t, err, "构建消息不应返回错误")
+			assertEqual(t, tt.expect, message.Notification.ClickType, "个推通道应使用设置的点击动作")
+			assertEqual(t, ClickTypeStartApp, channel.Android.UPS.Notification.ClickType, "Android厂商通道不支持时应打开应用首页")
+			assertEqual(t, HarmonyClickTypeStartApp, channel.Harmony.Notification.ClickType, "鸿蒙应打开应用首页")
+		})
+	}
+}
+
+func TestMessageBuilder_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *MessageBuilder
+	}{
+		{"缺少标题", NewMessage("", "内容")},
+		{"缺少URL", NewMessage("标题", "内容").ClickURL("")},
+		{"缺少intent", NewMessage("标题", "内容").ClickIntent("")},
+		{"缺少payload", NewMessage("标题", "内容").ClickPayload("")},
+		{"不启动应用缺少payload", NewMessage("标题", "内容").ClickPayloadCustom("")},
+		{"未知平台", NewMessage("标题", "内容").Platforms("windows")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.Build()
+			assertTrue(t, errors.Is(err, ErrInvalidPushMessage), "应返回ErrInvalidPushMessage")
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package getui
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option 客户端选项，在校验配置之前作用于Config
+type Option func(*Config)
+
+// Logger 日志接口，*log.Logger可直接使用
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Clock 时钟接口，用于判断token过期和统计失败窗口，测试时可替换
+type Clock interface {
+	Now() time.Time
+}
+
+// WithCredentials 设置应用凭证
+func WithCredentials(appID, appKey, masterSecret string) Option {
+	return func(c *Config) {
+		c.AppID = appID
+		c.AppKey = appKey
+		c.MasterSecret = masterSecret
+	}
+}
+
+// WithSecretProvider 设置MasterSecret提供者，此时可不设置MasterSecret
+func WithSecretProvider(provider SecretProvider) Option {
+	return func(c *Config) {
+		c.SecretProvider = provider
+	}
+}
+
+// WithDomains 设置候选域名，第一个作为默认域名
+func WithDomains(domains ...string) Option {
+	return func(c *Config) {
+		if len(domains) == 0 {
+			return
+		}
+		c.Domain = domains[0]
+		c.Domains = append([]string(nil), domains[1:]...)
+	}
+}
+
+// WithHTTPClient 使用自定义http.Client，优先于WithTransport，
+// 此时ConnectTimeout、TrustSSL和ProxyConfig不再生效
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport 使用自定义http.RoundTripper，此时ConnectTimeout、TrustSSL和ProxyConfig不再生效
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithRetryPolicy 设置重试策略
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithMaxResponseBodySize 设置响应体最大字节数
+func WithMaxResponseBodySize(size int) Option {
+	return func(c *Config) {
+		c.MaxResponseBodySize = size
+	}
+}
+
+// WithLogger 设置日志
+func WithLogger(logger Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithTokenStore 设置多实例共享token的存储
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Config) {
+		c.TokenStore = store
+	}
+}
+
+// WithClock 设置时钟
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithProxy 设置代理
+func WithProxy(proxy *HTTPProxyConfig) Option {
+	return func(c *Config) {
+		c.ProxyConfig = proxy
+	}
+}
+
+// WithSocketTimeout 设置读取超时
+func WithSocketTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.SocketTimeout = durationToMillis(timeout)
+	}
+}
+
+// WithConnectTimeout 设置连接超时
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ConnectTimeout = durationToMillis(timeout)
+	}
+}
+
+// WithConnectionRequestTimeout 设置从连接池获取连接的超时
+func WithConnectionRequestTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ConnectionRequestTimeout = durationToMillis(timeout)
+	}
+}
+
+// WithURITimeout 设置指定接口的读取超时，uri格式同URIToSocketTimeoutMap
+func WithURITimeout(uri string, timeout time.Duration) Option {
+	return func(c *Config) {
+		if c.URIToSocketTimeoutMap == nil {
+			c.URIToSocketTimeoutMap = make(map[string]int)
+		}
+		c.URIToSocketTimeoutMap[uri] = durationToMillis(timeout)
+	}
+}
+
+// WithTokenExpireMargin 设置token过期余量
+func WithTokenExpireMargin(margin time.Duration) Option {
+	return func(c *Config) {
+		c.TokenExpireMargin = margin
+	}
+}
+
+// WithTokenAutoRefresh 开启后台刷新token，在过期前ahead主动续期
+func WithTokenAutoRefresh(ahead time.Duration) Option {
+	return func(c *Config) {
+		c.TokenAutoRefresh = true
+		c.TokenAutoRefreshAhead = ahead
+	}
+}
+
+// WithStableDomainAnalysis 开启稳定域名检测
+func WithStableDomainAnalysis(interval time.Duration) Option {
+	return func(c *Config) {
+		c.OpenAnalyseStableDomain = true
+		c.AnalyseStableDomainInterval = interval
+	}
+}
+
+// WithHealthCheck 开启健康检测
+func WithHealthCheck(interval time.Duration) Option {
+	return func(c *Config) {
+		c.OpenCheckHealthDataSwitch = true
+		c.CheckHealthInterval = interval
+	}
+}
+
+// durationToMillis 将时间转换为毫秒，不足1毫秒的正数按1毫秒计，避免被当作不限制
+func durationToMillis(d time.Duration) int {
+	if d > 0 && d < time.Millisecond {
+		return 1
+	}
+	return int(d / time.Millisecond)
+}
+
+// now 获取当前时间
+func (c *Config) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock.Now()
+	}
+	return time.Now()
+}
+
+// logf 输出日志，未设置Logger时忽略
+func (c *Config) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf("[getui] "+format, v...)
+	}
+}
@@ -0,0 +1,151 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// roundTripFunc 用函数实现http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeClock 可手动调整的时钟
+type fakeClock struct {
+	now atomic.Int64
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	clock := &fakeClock{}
+	clock.now.Store(now.UnixNano())
+	return clock
+}
+
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, c.now.Load())
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now.Add(int64(d))
+}
+
+func TestNewClientWithOptions_Options(t *testing.T) {
+	store := NewMemoryTokenStore()
+	policy := NewDefaultRetryPolicy(3)
+	client, err := NewClientWithOptions(nil,
+		WithCredentials("app_id", "app_key", "master_secret"),
+		WithDomains("https://api1.getui.com/v2", "https://api2.getui.com/v2"),
+		WithSocketTimeout(5*time.Second),
+		WithConnectTimeout(500*time.Microsecond),
+		WithConnectionRequestTimeout(time.Second),
+		WithURITimeout("/task/{task_id}", 3*time.Second),
+		WithRetryPolicy(policy),
+		WithTokenStore(store),
+	)
+	assertNoError(t, err, "创建客户端不应返回错误")
+	defer client.Close()
+
+	config := client.GetConfig()
+	assertEqual(t, "app_id", config.AppID, "应设置AppID")
+	assertEqual(t, "https://api1.getui.com/v2", config.Domain, "第一个域名应作为默认域名")
+	assertEqual(t, 1, len(config.Domains), "其余域名应作为候选域名")
+	assertEqual(t, 5000, config.SocketTimeout, "读取超时应转换为毫秒")
+	assertEqual(t, 1, config.ConnectTimeout, "不足1毫秒的超时应按1毫秒计")
+	assertEqual(t, 1000, config.ConnectionRequestTimeout, "获取连接超时应转换为毫秒")
+	assertEqual(t, 3000, config.GetCustomSocketTimeout("/task/RASA_123"), "应设置接口超时")
+	assertTrue(t, config.GetRetryPolicy() == policy, "应使用设置的重试策略")
+	assertTrue(t, config.TokenStore == store, "应使用设置的TokenStore")
+
+	_, err = NewClientWithOptions(nil, WithCredentials("app_id", "app_key", "master_secret"), WithSocketTimeout(-time.Second))
+	assertError(t, err, "负数超时应返回错误")
+}
+
+func TestNewClientWithOptions_SharedConfig(t *testing.T) {
+	config := getTestConfig()
+	appID := config.AppID
+
+	first := NewClient(config)
+	defer first.Close()
+	second, err := NewClientWithOptions(config,
+		WithCredentials("other_app", "other_key", "other_secret"),
+		WithURITimeout("/push/all", time.Second))
+	assertNoError(t, err, "创建客户端不应返回错误")
+	defer second.Close()
+
+	assertEqual(t, appID, config.AppID, "不应修改调用方的配置")
+	assertEqual(t, appID, first.GetConfig().AppID, "不应影响使用同一配置创建的客户端")
+	assertEqual(t, "other_app", second.GetConfig().AppID, "选项应作用于新客户端")
+	_, exists := config.URIToSocketTimeoutMap["/push/all"]
+	assertFalse(t, exists, "不应修改调用方的URIToSocketTimeoutMap")
+	assertTrue(t, first.GetConfig() != second.GetConfig(), "两个客户端不应共享配置")
+}
+
+func TestNewClientWithOptions_Transport(t *testing.T) {
+	var requests int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		body := `{"code":0,"msg":"success","data":{"token":"transport_token"}}`
+		if !strings.HasSuffix(req.URL.Path, "/auth") {
+			body = `{"code":0,"msg":"success"}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	client, err := NewClientWithOptions(getTestConfig(), WithTransport(transport))
+	assertNoError(t, err, "创建客户端不应返回错误")
+	defer client.Close()
+
+	result, err := client.DoRequest("GET", "/user/count", nil)
+	assertNoError(t, err, "请求不应返回错误")
+	assertTrue(t, result.IsSuccess(), "请求应成功")
+	assertEqual(t, int32(2), atomic.LoadInt32(&requests), "鉴权和请求都应通过注入的Transport")
+
+	httpClient := &http.Client{Transport: transport}
+	client, err = NewClientWithOptions(getTestConfig(), WithHTTPClient(httpClient))
+	assertNoError(t, err, "创建客户端不应返回错误")
+	defer client.Close()
+	assertTrue(t, client.httpClient == httpClient, "应使用注入的http.Client")
+}
+
+func TestNewClientWithOptions_ClockAndLogger(t *testing.T) {
+	var authCount int32
+	server := createAuthTestServer(&authCount, 0)
+	defer server.Close()
+
+	clock := newFakeClock(time.Now())
+	var logs bytes.Buffer
+	client, err := NewClientWithOptions(getTestConfig(),
+		WithDomains(server.URL),
+		WithClock(clock),
+		WithLogger(log.New(&logs, "", 0)),
+		WithTokenStore(failingTokenStore{NewMemoryTokenStore()}),
+	)
+	assertNoError(t, err, "创建客户端不应返回错误")
+	defer client.Close()
+
+	token, err := client.GetToken()
+	assertNoError(t, err, "获取token不应返回错误")
+	assertEqual(t, "token_1", token, "应获取新token")
+	assertTrue(t, strings.Contains(logs.String(), "failed to save token to store"), "保存token失败时应输出日志")
+
+	clock.Advance(24 * time.Hour)
+	assertTrue(t, client.GetTokenManager().IsTokenExpired(), "时钟前进后token应过期")
+}
+
+// failingTokenStore 保存总是失败的TokenStore
+type failingTokenStore struct {
+	*MemoryTokenStore
+}
+
+func (failingTokenStore) Set(ctx context.Context, key string, token *StoredToken) error {
+	return io.ErrClosedPipe
+}
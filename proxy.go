@@ -0,0 +1,112 @@
+package getui
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 代理协议
+const (
+	ProxySchemeHTTP   = "http"
+	ProxySchemeHTTPS  = "https"
+	ProxySchemeSOCKS5 = "socks5"
+)
+
+// URL 获取代理地址，Scheme为空时默认使用HTTP代理
+func (p *HTTPProxyConfig) URL() (*url.URL, error) {
+	if p.Host == "" {
+		return nil, &ConfigError{Field: "proxy_config.host", Message: "proxy host is required"}
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		return nil, &ConfigError{Field: "proxy_config.port", Message: fmt.Sprintf("invalid proxy port %d", p.Port)}
+	}
+
+	scheme := strings.ToLower(p.Scheme)
+	switch scheme {
+	case "":
+		scheme = ProxySchemeHTTP
+	case ProxySchemeHTTP, ProxySchemeHTTPS, ProxySchemeSOCKS5:
+	default:
+		return nil, &ConfigError{Field: "proxy_config.scheme", Message: fmt.Sprintf("unsupported proxy scheme %q", p.Scheme)}
+	}
+
+	proxyURL := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(p.Host, strconv.Itoa(p.Port)),
+	}
+	if p.Username != "" {
+		proxyURL.User = url.UserPassword(p.Username, p.Password)
+	}
+	return proxyURL, nil
+}
+
+// ProxyFunc 获取http.Transport使用的代理函数，命中NoProxy的请求直连
+func (p *HTTPProxyConfig) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	proxyURL, err := p.URL()
+	return func(req *http.Request) (*url.URL, error) {
+		if err != nil {
+			return nil, err
+		}
+		if p.bypass(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}
+}
+
+// bypass 判断请求地址是否命中NoProxy列表，规则与NO_PROXY环境变量一致：
+// "*"匹配所有地址，IP和CIDR按地址匹配，域名匹配自身及其子域名，可带端口
+func (p *HTTPProxyConfig) bypass(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" {
+		if target.Scheme == "https" {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+	ip := net.ParseIP(host)
+
+	for _, rule := range p.NoProxy {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if rule == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(rule); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		ruleHost, rulePort := rule, ""
+		if h, hp, err := net.SplitHostPort(rule); err == nil {
+			ruleHost, rulePort = h, hp
+		}
+		if rulePort != "" && rulePort != port {
+			continue
+		}
+
+		if ruleIP := net.ParseIP(ruleHost); ruleIP != nil {
+			if ip != nil && ruleIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		ruleHost = strings.TrimPrefix(strings.TrimPrefix(ruleHost, "*"), ".")
+		if host == ruleHost || strings.HasSuffix(host, "."+ruleHost) {
+			return true
+		}
+	}
+	return false
+}
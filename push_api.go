@@ -1,6 +1,7 @@
 package getui
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -10,8 +11,13 @@ type PushAPI struct {
 }
 
 // PushToSingleByCID 根据CID单推
-func (api *PushAPI) PushToSingleByCID(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) PushToSingleByCID(pushDTO *PushDTO) (*PushResult, error) {
+	return api.PushToSingleByCIDContext(context.Background(), pushDTO)
+}
+
+// PushToSingleByCIDContext 根据CID单推（支持context）
+func (api *PushAPI) PushToSingleByCIDContext(ctx context.Context, pushDTO *PushDTO) (*PushResult, error) {
+	if err := api.validatePushDTO(pushDTO, "/push/single/cid", AudienceKindCID); err != nil {
 		return nil, err
 	}
 
@@ -20,12 +26,17 @@ func (api *PushAPI) PushToSingleByCID(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/single/cid", pushDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/single/cid", pushDTO))
 }
 
 // PushToSingleByAlias 根据别名单推
-func (api *PushAPI) PushToSingleByAlias(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) PushToSingleByAlias(pushDTO *PushDTO) (*PushResult, error) {
+	return api.PushToSingleByAliasContext(context.Background(), pushDTO)
+}
+
+// PushToSingleByAliasContext 根据别名单推（支持context）
+func (api *PushAPI) PushToSingleByAliasContext(ctx context.Context, pushDTO *PushDTO) (*PushResult, error) {
+	if err := api.validatePushDTO(pushDTO, "/push/single/alias", AudienceKindAlias); err != nil {
 		return nil, err
 	}
 
@@ -33,12 +44,17 @@ func (api *PushAPI) PushToSingleByAlias(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/single/alias", pushDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/single/alias", pushDTO))
 }
 
 // PushBatchByCID 根据CID批量推送
-func (api *PushAPI) PushBatchByCID(batchDTO *PushBatchDTO) (*ApiResult, error) {
-	if err := api.validatePushBatchDTO(batchDTO); err != nil {
+func (api *PushAPI) PushBatchByCID(batchDTO *PushBatchDTO) (*PushResult, error) {
+	return api.PushBatchByCIDContext(context.Background(), batchDTO)
+}
+
+// PushBatchByCIDContext 根据CID批量推送（支持context）
+func (api *PushAPI) PushBatchByCIDContext(ctx context.Context, batchDTO *PushBatchDTO) (*PushResult, error) {
+	if err := api.validatePushBatchDTO(batchDTO, "/push/single/batch/cid", AudienceKindCID); err != nil {
 		return nil, err
 	}
 
@@ -46,12 +62,17 @@ func (api *PushAPI) PushBatchByCID(batchDTO *PushBatchDTO) (*ApiResult, error) {
 		batchDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/single/batch/cid", batchDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/single/batch/cid", batchDTO))
 }
 
 // PushBatchByAlias 根据别名批量推送
-func (api *PushAPI) PushBatchByAlias(batchDTO *PushBatchDTO) (*ApiResult, error) {
-	if err := api.validatePushBatchDTO(batchDTO); err != nil {
+func (api *PushAPI) PushBatchByAlias(batchDTO *PushBatchDTO) (*PushResult, error) {
+	return api.PushBatchByAliasContext(context.Background(), batchDTO)
+}
+
+// PushBatchByAliasContext 根据别名批量推送（支持context）
+func (api *PushAPI) PushBatchByAliasContext(ctx context.Context, batchDTO *PushBatchDTO) (*PushResult, error) {
+	if err := api.validatePushBatchDTO(batchDTO, "/push/single/batch/alias", AudienceKindAlias); err != nil {
 		return nil, err
 	}
 
@@ -59,12 +80,22 @@ func (api *PushAPI) PushBatchByAlias(batchDTO *PushBatchDTO) (*ApiResult, error)
 		batchDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/single/batch/alias", batchDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/single/batch/alias", batchDTO))
 }
 
 // PushAll 群推
-func (api *PushAPI) PushAll(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) PushAll(pushDTO *PushDTO) (*TaskResult, error) {
+	return api.PushAllContext(context.Background(), pushDTO)
+}
+
+// PushAllContext 群推（支持context）
+func (api *PushAPI) PushAllContext(ctx context.Context, pushDTO *PushDTO) (*TaskResult, error) {
+	// 未指定受众时推送给全部用户
+	if pushDTO != nil && pushDTO.Audience == nil {
+		pushDTO.Audience = All()
+	}
+
+	if err := api.validatePushDTO(pushDTO, "/push/all", AudienceKindAll); err != nil {
 		return nil, err
 	}
 
@@ -72,15 +103,17 @@ func (api *PushAPI) PushAll(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	// 群推时Audience设置为"all"
-	pushDTO.Audience = "all"
-
-	return api.client.DoRequest("POST", "/push/all", pushDTO)
+	return newTaskResult(api.client.DoRequestContext(ctx, "POST", "/push/all", pushDTO))
 }
 
 // PushByTag 根据标签推送
-func (api *PushAPI) PushByTag(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) PushByTag(pushDTO *PushDTO) (*TaskResult, error) {
+	return api.PushByTagContext(context.Background(), pushDTO)
+}
+
+// PushByTagContext 根据标签推送（支持context）
+func (api *PushAPI) PushByTagContext(ctx context.Context, pushDTO *PushDTO) (*TaskResult, error) {
+	if err := api.validatePushDTO(pushDTO, "/push/tag", AudienceKindTag); err != nil {
 		return nil, err
 	}
 
@@ -88,12 +121,17 @@ func (api *PushAPI) PushByTag(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/tag", pushDTO)
+	return newTaskResult(api.client.DoRequestContext(ctx, "POST", "/push/tag", pushDTO))
 }
 
 // PushByFastCustomTag 使用标签快速推送
-func (api *PushAPI) PushByFastCustomTag(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) PushByFastCustomTag(pushDTO *PushDTO) (*TaskResult, error) {
+	return api.PushByFastCustomTagContext(context.Background(), pushDTO)
+}
+
+// PushByFastCustomTagContext 使用标签快速推送（支持context）
+func (api *PushAPI) PushByFastCustomTagContext(ctx context.Context, pushDTO *PushDTO) (*TaskResult, error) {
+	if err := api.validatePushDTO(pushDTO, "/push/fast_custom_tag", AudienceKindFastCustomTag); err != nil {
 		return nil, err
 	}
 
@@ -101,12 +139,17 @@ func (api *PushAPI) PushByFastCustomTag(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/fast_custom_tag", pushDTO)
+	return newTaskResult(api.client.DoRequestContext(ctx, "POST", "/push/fast_custom_tag", pushDTO))
 }
 
 // CreateMsg 创建消息体
-func (api *PushAPI) CreateMsg(pushDTO *PushDTO) (*ApiResult, error) {
-	if err := api.validatePushDTO(pushDTO); err != nil {
+func (api *PushAPI) CreateMsg(pushDTO *PushDTO) (*TaskResult, error) {
+	return api.CreateMsgContext(context.Background(), pushDTO)
+}
+
+// CreateMsgContext 创建消息体（支持context）
+func (api *PushAPI) CreateMsgContext(ctx context.Context, pushDTO *PushDTO) (*TaskResult, error) {
+	if err := api.validatePushDTO(pushDTO, "/push/list/message", AudienceKindNone); err != nil {
 		return nil, err
 	}
 
@@ -114,12 +157,17 @@ func (api *PushAPI) CreateMsg(pushDTO *PushDTO) (*ApiResult, error) {
 		pushDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/list/message", pushDTO)
+	return newTaskResult(api.client.DoRequestContext(ctx, "POST", "/push/list/message", pushDTO))
 }
 
 // PushListByCID 根据CID列表推送
-func (api *PushAPI) PushListByCID(audienceDTO *AudienceDTO) (*ApiResult, error) {
-	if err := api.validateAudienceDTO(audienceDTO); err != nil {
+func (api *PushAPI) PushListByCID(audienceDTO *AudienceDTO) (*PushResult, error) {
+	return api.PushListByCIDContext(context.Background(), audienceDTO)
+}
+
+// PushListByCIDContext 根据CID列表推送（支持context）
+func (api *PushAPI) PushListByCIDContext(ctx context.Context, audienceDTO *AudienceDTO) (*PushResult, error) {
+	if err := api.validateAudienceDTO(audienceDTO, "/push/list/cid", AudienceKindCID); err != nil {
 		return nil, err
 	}
 
@@ -127,12 +175,17 @@ func (api *PushAPI) PushListByCID(audienceDTO *AudienceDTO) (*ApiResult, error)
 		audienceDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/list/cid", audienceDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/list/cid", audienceDTO))
 }
 
 // PushListByAlias 根据别名列表推送
-func (api *PushAPI) PushListByAlias(audienceDTO *AudienceDTO) (*ApiResult, error) {
-	if err := api.validateAudienceDTO(audienceDTO); err != nil {
+func (api *PushAPI) PushListByAlias(audienceDTO *AudienceDTO) (*PushResult, error) {
+	return api.PushListByAliasContext(context.Background(), audienceDTO)
+}
+
+// PushListByAliasContext 根据别名列表推送（支持context）
+func (api *PushAPI) PushListByAliasContext(ctx context.Context, audienceDTO *AudienceDTO) (*PushResult, error) {
+	if err := api.validateAudienceDTO(audienceDTO, "/push/list/alias", AudienceKindAlias); err != nil {
 		return nil, err
 	}
 
@@ -140,38 +193,53 @@ func (api *PushAPI) PushListByAlias(audienceDTO *AudienceDTO) (*ApiResult, error
 		audienceDTO.RequestID = api.client.GenerateRequestID()
 	}
 
-	return api.client.DoRequest("POST", "/push/list/alias", audienceDTO)
+	return newPushResult(api.client.DoRequestContext(ctx, "POST", "/push/list/alias", audienceDTO))
 }
 
 // StopPush 停止推送任务
 func (api *PushAPI) StopPush(taskID string) (*ApiResult, error) {
+	return api.StopPushContext(context.Background(), taskID)
+}
+
+// StopPushContext 停止推送任务（支持context）
+func (api *PushAPI) StopPushContext(ctx context.Context, taskID string) (*ApiResult, error) {
 	if taskID == "" {
 		return nil, fmt.Errorf("task_id cannot be empty")
 	}
 
-	return api.client.DoRequest("DELETE", fmt.Sprintf("/task/%s", taskID), nil)
+	return checkResult(api.client.DoRequestContext(ctx, "DELETE", fmt.Sprintf("/task/%s", taskID), nil))
 }
 
 // QueryScheduleTask 查询定时任务
 func (api *PushAPI) QueryScheduleTask(taskID string) (*ApiResult, error) {
+	return api.QueryScheduleTaskContext(context.Background(), taskID)
+}
+
+// QueryScheduleTaskContext 查询定时任务（支持context）
+func (api *PushAPI) QueryScheduleTaskContext(ctx context.Context, taskID string) (*ApiResult, error) {
 	if taskID == "" {
 		return nil, fmt.Errorf("task_id cannot be empty")
 	}
 
-	return api.client.DoRequest("GET", fmt.Sprintf("/task/schedule/%s", taskID), nil)
+	return checkResult(api.client.DoRequestContext(ctx, "GET", fmt.Sprintf("/task/schedule/%s", taskID), nil))
 }
 
 // DeleteScheduleTask 删除定时任务
 func (api *PushAPI) DeleteScheduleTask(taskID string) (*ApiResult, error) {
+	return api.DeleteScheduleTaskContext(context.Background(), taskID)
+}
+
+// DeleteScheduleTaskContext 删除定时任务（支持context）
+func (api *PushAPI) DeleteScheduleTaskContext(ctx context.Context, taskID string) (*ApiResult, error) {
 	if taskID == "" {
 		return nil, fmt.Errorf("task_id cannot be empty")
 	}
 
-	return api.client.DoRequest("DELETE", fmt.Sprintf("/task/schedule/%s", taskID), nil)
+	return checkResult(api.client.DoRequestContext(ctx, "DELETE", fmt.Sprintf("/task/schedule/%s", taskID), nil))
 }
 
-// validatePushDTO 验证推送DTO
-func (api *PushAPI) validatePushDTO(pushDTO *PushDTO) error {
+// validatePushDTO 验证推送DTO，kind为接口要求的受众类型
+func (api *PushAPI) validatePushDTO(pushDTO *PushDTO, uri string, kind AudienceKind) error {
 	if pushDTO == nil {
 		return fmt.Errorf("push_dto cannot be nil")
 	}
@@ -180,8 +248,8 @@ func (api *PushAPI) validatePushDTO(pushDTO *PushDTO) error {
 		return ErrInvalidRequestID
 	}
 
-	if pushDTO.Audience == nil {
-		return ErrEmptyAudience
+	if err := validateAudience(pushDTO.Audience, uri, kind); err != nil {
+		return err
 	}
 
 	if pushDTO.PushMessage == nil {
@@ -192,7 +260,7 @@ func (api *PushAPI) validatePushDTO(pushDTO *PushDTO) error {
 }
 
 // validatePushBatchDTO 验证批量推送DTO
-func (api *PushAPI) validatePushBatchDTO(batchDTO *PushBatchDTO) error {
+func (api *PushAPI) validatePushBatchDTO(batchDTO *PushBatchDTO, uri string, kind AudienceKind) error {
 	if batchDTO == nil {
 		return fmt.Errorf("batch_dto cannot be nil")
 	}
@@ -201,8 +269,8 @@ func (api *PushAPI) validatePushBatchDTO(batchDTO *PushBatchDTO) error {
 		return ErrInvalidRequestID
 	}
 
-	if batchDTO.Audience == nil {
-		return ErrEmptyAudience
+	if err := validateAudience(batchDTO.Audience, uri, kind); err != nil {
+		return err
 	}
 
 	if batchDTO.PushMessage == nil {
@@ -213,7 +281,7 @@ func (api *PushAPI) validatePushBatchDTO(batchDTO *PushBatchDTO) error {
 }
 
 // validateAudienceDTO 验证受众DTO
-func (api *PushAPI) validateAudienceDTO(audienceDTO *AudienceDTO) error {
+func (api *PushAPI) validateAudienceDTO(audienceDTO *AudienceDTO, uri string, kind AudienceKind) error {
 	if audienceDTO == nil {
 		return fmt.Errorf("audience_dto cannot be nil")
 	}
@@ -222,9 +290,26 @@ func (api *PushAPI) validateAudienceDTO(audienceDTO *AudienceDTO) error {
 		return ErrInvalidRequestID
 	}
 
-	if audienceDTO.Audience == nil {
-		return ErrEmptyAudience
+	if err := validateAudience(audienceDTO.Audience, uri, kind); err != nil {
+		return err
 	}
 
 	return nil
 }
+
+// validateAudience 验证受众类型与接口匹配，kind为AudienceKindNone时不要求受众
+func validateAudience(audience *Audience, uri string, kind AudienceKind) error {
+	if kind == AudienceKindNone {
+		return nil
+	}
+	if audience == nil {
+		return ErrEmptyAudience
+	}
+	if err := audience.validateKind(uri, kind); err != nil {
+		return err
+	}
+	if kind == AudienceKindTag {
+		return TagFilter(audience.Tag).Validate()
+	}
+	return nil
+}
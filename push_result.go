@@ -0,0 +1,124 @@
+package getui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 单个CID的推送状态
+const (
+	PushStatusOnline  = "successed_online"  // 在线下发
+	PushStatusOffline = "successed_offline" // 离线下发（包含厂商通道）
+	PushStatusIgnore  = "successed_ignore"  // 长期不活跃，未下发
+)
+
+// PushResult 返回每个CID推送状态的推送结果，对应单推、批量单推和toList推送，
+// 响应数据格式为{"$taskid": {"$cid": "$status"}}。嵌入的ApiResult为原始响应
+type PushResult struct {
+	*ApiResult
+
+	// Tasks 任务ID到CID推送状态的映射，请求失败时为nil
+	Tasks map[string]map[string]string
+}
+
+// TaskID 获取任务ID，包含多个任务时返回排序后的第一个，批量推送请使用TaskIDs
+func (r *PushResult) TaskID() string {
+	if ids := r.TaskIDs(); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// TaskIDs 获取排序后的全部任务ID
+func (r *PushResult) TaskIDs() []string {
+	ids := make([]string, 0, len(r.Tasks))
+	for id := range r.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// PerCIDStatus 获取每个CID的推送状态，合并所有任务
+func (r *PushResult) PerCIDStatus() map[string]string {
+	statuses := make(map[string]string)
+	for _, cids := range r.Tasks {
+		for cid, status := range cids {
+			statuses[cid] = status
+		}
+	}
+	return statuses
+}
+
+// FailedCIDs 获取推送状态不是成功的CID，按字典序排列
+func (r *PushResult) FailedCIDs() []string {
+	var failed []string
+	for cid, status := range r.PerCIDStatus() {
+		if !IsPushStatusSuccess(status) {
+			failed = append(failed, cid)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// TaskResult 只返回任务ID的推送结果，对应创建消息、群推、标签推送和快速标签推送，
+// 响应数据格式为{"taskid": "$taskid"}。嵌入的ApiResult为原始响应
+type TaskResult struct {
+	*ApiResult
+
+	TaskIDDTO
+}
+
+// TaskID 获取任务ID
+func (r *TaskResult) TaskID() string {
+	return r.TaskIDDTO.TaskID
+}
+
+// IsPushStatusSuccess 判断CID推送状态是否为成功
+func IsPushStatusSuccess(status string) bool {
+	return strings.HasPrefix(status, "successed")
+}
+
+// newPushResult 将响应解析为PushResult，返回码非0时同时返回原始响应和APIError
+func newPushResult(result *ApiResult, err error) (*PushResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	pushResult := &PushResult{ApiResult: result}
+	if err := result.Err(); err != nil {
+		return pushResult, err
+	}
+	if err := decodeResultData(result, &pushResult.Tasks); err != nil {
+		return pushResult, err
+	}
+	return pushResult, nil
+}
+
+// newTaskResult 将响应解析为TaskResult，返回码非0时同时返回原始响应和APIError
+func newTaskResult(result *ApiResult, err error) (*TaskResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	taskResult := &TaskResult{ApiResult: result}
+	if err := result.Err(); err != nil {
+		return taskResult, err
+	}
+	if err := decodeResultData(result, &taskResult.TaskIDDTO); err != nil {
+		return taskResult, err
+	}
+	return taskResult, nil
+}
+
+// decodeResultData 请求成功且包含数据时解析响应数据
+func decodeResultData(result *ApiResult, v interface{}) error {
+	if !result.IsSuccess() || len(result.Data) == 0 || string(result.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		return fmt.Errorf("%w: failed to decode data: %v", ErrInvalidResponse, err)
+	}
+	return nil
+}
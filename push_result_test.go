@@ -0,0 +1,64 @@
+package getui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createResponseTestClient 创建请求固定返回body的测试客户端
+func createResponseTestClient(t *testing.T, body string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+	return client
+}
+
+func TestPushResult(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{
+		"RASA_2":{"cid_3":"successed_offline"},
+		"RASA_1":{"cid_1":"successed_online","cid_2":"failed"}
+	}}`)
+
+	result, err := client.PushAPI.PushBatchByCID(&PushBatchDTO{Audience: createTestAudience(), PushMessage: createTestPushMessage()})
+	assertNoError(t, err, "推送不应返回错误")
+	assertTrue(t, result.IsSuccess(), "应能访问原始响应")
+	assertEqual(t, "RASA_1", result.TaskID(), "应返回第一个任务ID")
+	assertEqual(t, 2, len(result.TaskIDs()), "应返回全部任务ID")
+	assertEqual(t, PushStatusOffline, result.PerCIDStatus()["cid_3"], "应合并所有任务的CID状态")
+	failed := result.FailedCIDs()
+	assertEqual(t, 1, len(failed), "应只有一个失败的CID")
+	assertEqual(t, "cid_2", failed[0], "应返回失败的CID")
+}
+
+func TestTaskResult(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"taskid":"RASA_123"}}`)
+
+	result, err := client.PushAPI.PushAll(&PushDTO{Audience: All(), PushMessage: createTestPushMessage()})
+	assertNoError(t, err, "群推不应返回错误")
+	assertEqual(t, "RASA_123", result.TaskID(), "应解析任务ID")
+	assertTrue(t, strings.Contains(string(result.Data), "RASA_123"), "应保留原始数据")
+}
+
+func TestPushResult_Failure(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":20001,"msg":"appkey not exist","data":"bad"}`)
+	result, err := client.PushAPI.PushToSingleByCID(&PushDTO{Audience: createTestAudience(), PushMessage: createTestPushMessage()})
+	assertErrorType(t, err, &APIError{}, "返回码非0时应返回APIError")
+	assertEqual(t, 20001, result.Code, "应保留返回码")
+	assertEqual(t, "", result.TaskID(), "请求失败时没有任务ID")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":["unexpected"]}`)
+	result, err = client.PushAPI.PushToSingleByCID(&PushDTO{Audience: createTestAudience(), PushMessage: createTestPushMessage()})
+	assertTrue(t, errors.Is(err, ErrInvalidResponse), "数据格式错误时应返回ErrInvalidResponse")
+	assertTrue(t, result != nil && result.IsSuccess(), "数据格式错误时仍应返回原始响应")
+}
@@ -0,0 +1,189 @@
+package getui
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// ClientRegistry 多应用客户端注册表，按逻辑名称或AppID获取客户端。
+// 客户端在首次使用时创建，连接配置相同的客户端共享同一个连接池
+type ClientRegistry struct {
+	opts       []Option
+	base       *Config
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	configs map[string]*Config
+	clients map[string]*Client
+	closed  bool
+}
+
+// NewClientRegistry 创建客户端注册表，configs的键为逻辑名称。
+// opts作用于每个客户端，其中的连接配置（如WithTransport、WithProxy、WithConnectTimeout）也用于创建共享的连接池。
+// 应用了opts后未单独指定HTTPClient或Transport、且ProxyConfig、TrustSSL、ConnectTimeout与共享连接池一致的客户端
+// 使用共享连接池，连接配置不同的客户端按自己的配置创建独立的连接池
+func NewClientRegistry(configs map[string]*Config, opts ...Option) *ClientRegistry {
+	base := NewDefaultConfig()
+	for _, opt := range opts {
+		opt(base)
+	}
+
+	r := &ClientRegistry{
+		opts:       opts,
+		base:       base,
+		httpClient: base.GetHTTPClient(),
+		configs:    make(map[string]*Config),
+		clients:    make(map[string]*Client),
+	}
+	for name, config := range configs {
+		r.configs[name] = config
+	}
+	return r
+}
+
+// Register 注册应用配置，已创建的同名客户端不受影响
+func (r *ClientRegistry) Register(name string, config *Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrRegistryClosed
+	}
+	if _, exists := r.configs[name]; exists {
+		return fmt.Errorf("%w: %s", ErrTenantExists, name)
+	}
+	r.configs[name] = config
+	return nil
+}
+
+// Client 获取客户端，key可以是逻辑名称或AppID，首次获取时创建
+func (r *ClientRegistry) Client(key string) (*Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, ErrRegistryClosed
+	}
+
+	name, config := r.lookup(key)
+	if config == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, key)
+	}
+	if client, ok := r.clients[name]; ok {
+		return client, nil
+	}
+
+	opts := append(append([]Option(nil), r.opts...), r.shareHTTPClient)
+	client, err := NewClientWithOptions(config, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s: %w", name, err)
+	}
+	r.clients[name] = client
+	return client, nil
+}
+
+// PushAPI 获取指定应用的推送接口
+func (r *ClientRegistry) PushAPI(key string) (*PushAPI, error) {
+	client, err := r.Client(key)
+	if err != nil {
+		return nil, err
+	}
+	return client.PushAPI, nil
+}
+
+// UserAPI 获取指定应用的用户接口
+func (r *ClientRegistry) UserAPI(key string) (*UserAPI, error) {
+	client, err := r.Client(key)
+	if err != nil {
+		return nil, err
+	}
+	return client.UserAPI, nil
+}
+
+// StatisticAPI 获取指定应用的统计接口
+func (r *ClientRegistry) StatisticAPI(key string) (*StatisticAPI, error) {
+	client, err := r.Client(key)
+	if err != nil {
+		return nil, err
+	}
+	return client.StatisticAPI, nil
+}
+
+// Names 获取已注册的逻辑名称
+func (r *ClientRegistry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.configs))
+	for name := range r.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close 关闭所有已创建的客户端，返回第一个错误
+func (r *ClientRegistry) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	clients := r.clients
+	r.clients = make(map[string]*Client)
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(clients))
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			if err := client.Close(); err != nil {
+				errs <- err
+			}
+		}(client)
+	}
+	wg.Wait()
+	close(errs)
+
+	r.httpClient.CloseIdleConnections()
+	return <-errs
+}
+
+// lookup 按逻辑名称查找配置，找不到时按AppID查找，调用方需持有锁
+func (r *ClientRegistry) lookup(key string) (string, *Config) {
+	if config, ok := r.configs[key]; ok {
+		return key, config
+	}
+	for name, config := range r.configs {
+		if config != nil && config.AppID == key {
+			return name, config
+		}
+	}
+	return "", nil
+}
+
+// shareHTTPClient 未单独指定HTTPClient或Transport且连接配置与共享连接池一致时使用共享的连接池
+func (r *ClientRegistry) shareHTTPClient(c *Config) {
+	if c.HTTPClient == nil && c.Transport == nil && sameTransportConfig(c, r.base) {
+		c.HTTPClient = r.httpClient
+	}
+}
+
+// sameTransportConfig 判断两个配置的代理、证书校验和连接超时是否相同
+func sameTransportConfig(a, b *Config) bool {
+	if a.TrustSSL != b.TrustSSL || a.ConnectTimeout != b.ConnectTimeout {
+		return false
+	}
+	pa, pb := a.ProxyConfig, b.ProxyConfig
+	if pa == nil || pb == nil {
+		return pa == pb
+	}
+	return pa.Scheme == pb.Scheme && pa.Host == pb.Host && pa.Port == pb.Port &&
+		pa.Username == pb.Username && pa.Password == pb.Password && slices.Equal(pa.NoProxy, pb.NoProxy)
+}
@@ -0,0 +1,157 @@
+package getui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func createRegistryTestConfig(appID, domain string) *Config {
+	config := NewDefaultConfig()
+	config.AppID = appID
+	config.AppKey = appID + "_key"
+	config.MasterSecret = appID + "_secret"
+	config.Domain = domain
+	return config
+}
+
+func TestClientRegistry_Routing(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/auth") {
+			w.Write([]byte(`{"code":0,"msg":"success","data":{"token":"token"}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	registry := NewClientRegistry(map[string]*Config{
+		"brand_a": createRegistryTestConfig("app_a", server.URL),
+		"brand_b": createRegistryTestConfig("app_b", server.URL),
+	})
+	defer registry.Close()
+
+	assertEqual(t, 2, len(registry.Names()), "应包含所有注册的应用")
+
+	clientA, err := registry.Client("brand_a")
+	assertNoError(t, err, "按名称获取客户端不应返回错误")
+	clientByAppID, err := registry.Client("app_a")
+	assertNoError(t, err, "按AppID获取客户端不应返回错误")
+	assertTrue(t, clientA == clientByAppID, "名称和AppID应对应同一个客户端")
+
+	clientB, err := registry.Client("brand_b")
+	assertNoError(t, err, "获取客户端不应返回错误")
+	assertTrue(t, clientA.httpClient == clientB.httpClient, "客户端应共享连接池")
+	assertFalse(t, clientA.GetTokenManager() == clientB.GetTokenManager(), "每个应用应有独立的token")
+
+	userAPI, err := registry.UserAPI("brand_b")
+	assertNoError(t, err, "获取用户接口不应返回错误")
+	_, err = userAPI.GetUserCount()
+	assertNoError(t, err, "请求不应返回错误")
+
+	mu.Lock()
+	assertEqual(t, "/app_b/auth", paths[0], "应使用对应应用鉴权")
+	assertTrue(t, strings.HasPrefix(paths[1], "/app_b/"), "请求应路由到对应应用")
+	mu.Unlock()
+
+	_, err = registry.PushAPI("brand_c")
+	assertTrue(t, errors.Is(err, ErrUnknownTenant), "未注册的应用应返回ErrUnknownTenant")
+}
+
+func TestClientRegistry_Lazy(t *testing.T) {
+	var requests int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return nil, errors.New("unexpected request")
+	})
+
+	invalid := createRegistryTestConfig("app_invalid", "https://restapi.getui.com/v2")
+	invalid.AppKey = ""
+	registry := NewClientRegistry(map[string]*Config{"invalid": invalid}, WithTransport(transport))
+
+	assertNoError(t, registry.Register("brand_a", createRegistryTestConfig("app_a", "https://restapi.getui.com/v2")), "注册应用不应返回错误")
+	assertTrue(t, errors.Is(registry.Register("brand_a", NewDefaultConfig()), ErrTenantExists), "重复注册应返回错误")
+
+	_, err := registry.Client("invalid")
+	assertTrue(t, errors.Is(err, ErrAppKeyRequired), "配置无效时应返回配置错误")
+
+	client, err := registry.Client("brand_a")
+	assertNoError(t, err, "获取客户端不应返回错误")
+	assertEqual(t, int32(0), atomic.LoadInt32(&requests), "创建客户端时不应发起请求")
+
+	client.GetToken()
+	assertEqual(t, int32(1), atomic.LoadInt32(&requests), "应使用注入的Transport")
+
+	assertNoError(t, registry.Close(), "关闭注册表不应返回错误")
+	_, err = registry.Client("brand_a")
+	assertTrue(t, errors.Is(err, ErrRegistryClosed), "关闭后不应再创建客户端")
+}
+
+func TestClientRegistry_TransportConfig(t *testing.T) {
+	proxied := createRegistryTestConfig("app_proxy", "https://restapi.getui.com/v2")
+	proxied.ProxyConfig = &HTTPProxyConfig{Host: "proxy.internal", Port: 8080}
+	insecure := createRegistryTestConfig("app_insecure", "https://restapi.getui.com/v2")
+	insecure.TrustSSL = true
+
+	registry := NewClientRegistry(map[string]*Config{
+		"brand_a":  createRegistryTestConfig("app_a", "https://restapi.getui.com/v2"),
+		"brand_b":  createRegistryTestConfig("app_b", "https://restapi.getui.com/v2"),
+		"proxied":  proxied,
+		"insecure": insecure,
+	})
+	defer registry.Close()
+
+	clientA, _ := registry.Client("brand_a")
+	clientB, _ := registry.Client("brand_b")
+	assertTrue(t, clientA.httpClient == registry.httpClient, "连接配置相同时应使用共享连接池")
+	assertTrue(t, clientA.httpClient == clientB.httpClient, "连接配置相同的客户端应共享连接池")
+
+	clientProxy, err := registry.Client("proxied")
+	assertNoError(t, err, "获取客户端不应返回错误")
+	assertFalse(t, clientProxy.httpClient == registry.httpClient, "代理配置不同时不应使用共享连接池")
+	proxyURL, err := clientProxy.httpClient.Transport.(*http.Transport).Proxy(httptest.NewRequest("GET", "https://restapi.getui.com/v2", nil))
+	assertNoError(t, err, "获取代理不应返回错误")
+	assertEqual(t, "proxy.internal:8080", proxyURL.Host, "应使用应用自己的代理")
+
+	clientInsecure, err := registry.Client("insecure")
+	assertNoError(t, err, "获取客户端不应返回错误")
+	assertTrue(t, clientInsecure.httpClient.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify, "应使用应用自己的TrustSSL")
+}
+
+func TestClientRegistry_DoesNotModifyConfig(t *testing.T) {
+	config := createRegistryTestConfig("app_a", "https://restapi.getui.com/v2")
+	config.Domains = []string{"https://restapi2.getui.com/v2"}
+	config.RetryPolicy = NewDefaultRetryPolicy(2)
+	config.OpenAnalyseStableDomain = false
+
+	registry := NewClientRegistry(map[string]*Config{"brand_a": config},
+		WithURITimeout("/push/all", time.Second),
+		func(c *Config) {
+			if len(c.Domains) > 0 {
+				c.Domains[0] = "https://changed.getui.com/v2"
+			}
+			if c.RetryPolicy != nil {
+				c.RetryPolicy.MaxAttempts = 5
+			}
+		})
+	defer registry.Close()
+
+	client, err := registry.Client("brand_a")
+	assertNoError(t, err, "创建客户端不应返回错误")
+	assertEqual(t, 1000, client.GetConfig().URIToSocketTimeoutMap["/push/all"], "选项应作用于客户端配置")
+
+	_, exists := config.URIToSocketTimeoutMap["/push/all"]
+	assertFalse(t, exists, "不应修改调用方的URIToSocketTimeoutMap")
+	assertEqual(t, "https://restapi2.getui.com/v2", config.Domains[0], "不应修改调用方的Domains")
+	assertEqual(t, 2, config.RetryPolicy.MaxAttempts, "不应修改调用方的RetryPolicy")
+}
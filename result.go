@@ -0,0 +1,68 @@
+package getui
+
+import (
+	"context"
+)
+
+// Result 泛型API响应结果，Data为解析后的响应数据
+type Result[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+// IsSuccess 判断请求是否成功
+func (r *Result[T]) IsSuccess() bool {
+	return r.Code == 0
+}
+
+// Err 返回码非0时返回APIError，否则返回nil
+func (r *Result[T]) Err() error {
+	if r.IsSuccess() {
+		return nil
+	}
+	return &APIError{Code: r.Code, Message: r.Msg}
+}
+
+// ParseResult 将原始响应解析为Result。返回码非0时返回Result和APIError，
+// 数据格式错误时返回Result和ErrInvalidResponse
+func ParseResult[T any](result *ApiResult) (*Result[T], error) {
+	typed := &Result[T]{Code: result.Code, Msg: result.Msg}
+	if err := result.Err(); err != nil {
+		return typed, err
+	}
+	if err := decodeResultData(result, &typed.Data); err != nil {
+		return typed, err
+	}
+	return typed, nil
+}
+
+// DoResult 执行请求并将响应解析为Result，返回码非0时返回APIError
+func DoResult[T any](ctx context.Context, client *Client, method, uri string, body interface{}) (*Result[T], error) {
+	result, err := client.DoRequestContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResult[T](result)
+}
+
+// Call 执行请求并返回解析后的响应数据，返回码非0时返回APIError。
+// 用于调用SDK未封装的接口：
+//
+//	count, err := getui.Call[map[string]int64](ctx, client, "GET", "/user/count", nil)
+func Call[T any](ctx context.Context, client *Client, method, uri string, body interface{}) (T, error) {
+	result, err := DoResult[T](ctx, client, method, uri, body)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result.Data, nil
+}
+
+// checkResult 返回码非0时返回原始响应和APIError，用于只关心是否成功的接口
+func checkResult(result *ApiResult, err error) (*ApiResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result, result.Err()
+}
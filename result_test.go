@@ -0,0 +1,45 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"user_count":7}}`)
+
+	count, err := Call[map[string]int64](context.Background(), client, "GET", "/user/count", nil)
+	assertNoError(t, err, "请求成功时不应返回错误")
+	assertEqual(t, int64(7), count["user_count"], "应解析响应数据")
+
+	result, err := DoResult[userCountDTO](context.Background(), client, "GET", "/user/count", nil)
+	assertNoError(t, err, "请求成功时不应返回错误")
+	assertTrue(t, result.IsSuccess(), "返回码应为0")
+	assertEqual(t, int64(7), result.Data.UserCount, "应解析为指定类型")
+
+	_, err = Call[[]string](context.Background(), client, "GET", "/user/count", nil)
+	assertTrue(t, errors.Is(err, ErrInvalidResponse), "数据格式错误时应返回ErrInvalidResponse")
+}
+
+func TestCall_APIError(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":20001,"msg":"appkey not exist","data":{"user_count":7}}`)
+
+	count, err := Call[userCountDTO](context.Background(), client, "GET", "/user/count", nil)
+	var apiErr *APIError
+	assertTrue(t, errors.As(err, &apiErr), "返回码非0时应返回APIError")
+	assertEqual(t, 20001, apiErr.Code, "应保留返回码")
+	assertEqual(t, int64(0), count.UserCount, "返回码非0时应返回零值")
+
+	result, err := DoResult[userCountDTO](context.Background(), client, "GET", "/user/count", nil)
+	assertErrorType(t, err, &APIError{}, "返回码非0时应返回APIError")
+	assertEqual(t, "appkey not exist", result.Msg, "应同时返回响应")
+
+	raw, err := client.UserAPI.BindAlias("user_1", "cid_1")
+	assertErrorType(t, err, &APIError{}, "返回原始响应的接口也应返回APIError")
+	assertEqual(t, 20001, raw.Code, "应同时返回原始响应")
+
+	raw, err = client.DoRequest("GET", "/user/count", nil)
+	assertNoError(t, err, "DoRequest不检查返回码")
+	assertTrue(t, raw.Err() != nil, "ApiResult.Err应返回APIError")
+}
@@ -0,0 +1,115 @@
+package getui
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy HTTP重试策略
+type RetryPolicy struct {
+	MaxAttempts    int           `json:"max_attempts"`    // 最大尝试次数（包含首次请求）
+	InitialBackoff time.Duration `json:"initial_backoff"` // 首次重试前的等待时间
+	MaxBackoff     time.Duration `json:"max_backoff"`     // 最大等待时间
+	Multiplier     float64       `json:"multiplier"`      // 退避倍数
+	Jitter         float64       `json:"jitter"`          // 随机抖动比例(0~1)
+	RetryableCodes []int         `json:"retryable_codes"` // 可重试的个推返回码，为nil时按返回码目录判断
+}
+
+// NewDefaultRetryPolicy 创建默认重试策略
+func NewDefaultRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// attempts 获取有效的最大尝试次数
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff 计算第attempt次重试前的等待时间，attempt从1开始
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if p == nil || p.InitialBackoff <= 0 {
+		return 0
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		backoff += backoff * jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// IsRetryableCode 判断个推返回码是否可重试，未指定RetryableCodes时按LookupErrorCode的分类判断
+func (p *RetryPolicy) IsRetryableCode(code int) bool {
+	if p == nil {
+		return false
+	}
+	if p.RetryableCodes == nil {
+		return LookupErrorCode(code).Category.Retryable()
+	}
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableStatus 判断HTTP状态码是否可重试
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError
+}
+
+// retry 按重试策略执行fn，fn返回true表示本次结果可重试。
+// 达到最大尝试次数或ctx结束时返回最后一次的错误
+func (p *RetryPolicy) retry(ctx context.Context, fn func() (bool, error)) error {
+	maxAttempts := p.attempts()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		retryable, err = fn()
+		if !retryable || attempt >= maxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		// 服务端通过Retry-After要求等待时，等待时间不少于该值，超过MaxBackoff时不再重试
+		wait := p.Backoff(attempt)
+		if d := retryAfter(err); d > 0 {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				return err
+			}
+			if d > wait {
+				wait = d
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
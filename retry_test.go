@@ -0,0 +1,168 @@
+package getui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 创建指向测试服务器的客户端
+func createRetryTestClient(serverURL string, maxAttempts int) *Client {
+	config := getTestConfig()
+	config.Domain = serverURL
+	config.MaxHTTPTryTime = maxAttempts
+	config.RetryPolicy = &RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+	}
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+	return client
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	assertEqual(t, 100*time.Millisecond, policy.Backoff(1), "第1次重试等待时间")
+	assertEqual(t, 200*time.Millisecond, policy.Backoff(2), "第2次重试等待时间")
+	assertEqual(t, 300*time.Millisecond, policy.Backoff(3), "等待时间不应超过MaxBackoff")
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := policy.Backoff(1)
+		assertTrue(t, backoff >= 50*time.Millisecond && backoff <= 150*time.Millisecond, "抖动后的等待时间应在范围内")
+	}
+}
+
+func TestDoRequest_RetryReusesRequestID(t *testing.T) {
+	var attempts int32
+	var requestIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body PushDTO
+		json.NewDecoder(r.Body).Decode(&body)
+		requestIDs = append(requestIDs, body.RequestID)
+
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 3)
+	result, err := client.PushAPI.PushToSingleByCID(&PushDTO{
+		RequestID:   client.GenerateRequestID(),
+		PushMessage: createTestPushMessage(),
+		Audience:    createTestAudience(),
+	})
+
+	assertNoError(t, err, "重试后应该成功")
+	assertTrue(t, result.IsSuccess(), "重试后结果应该成功")
+	assertEqual(t, int32(3), atomic.LoadInt32(&attempts), "应该请求3次")
+	for _, id := range requestIDs {
+		assertEqual(t, requestIDs[0], id, "重试时request_id应该保持不变")
+	}
+}
+
+func TestDoRequest_RetryableCode(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Write([]byte(`{"code":50000,"msg":"system error"}`))
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 2)
+	result, err := client.DoRequest("GET", "/user/count", nil)
+
+	assertNoError(t, err, "返回码错误不应该返回error")
+	assertEqual(t, 50000, result.Code, "应该返回最后一次的结果")
+	assertEqual(t, int32(2), atomic.LoadInt32(&attempts), "可重试返回码应该触发重试")
+}
+
+func TestDoRequest_RetryableCodeFromCatalog(t *testing.T) {
+	tests := []struct {
+		code     int
+		attempts int32
+	}{
+		{10005, 3}, // 频率超限
+		{10006, 1}, // 配额超限
+		{20001, 1}, // 参数错误
+	}
+
+	for _, tt := range tests {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			fmt.Fprintf(w, `{"code":%d,"msg":"error"}`, tt.code)
+		}))
+
+		config := getTestConfig()
+		config.Domain = server.URL
+		config.MaxHTTPTryTime = 3
+		client := NewClient(config)
+		client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+		result, err := client.DoRequest("GET", "/user/count", nil)
+		server.Close()
+
+		assertNoError(t, err, "返回码错误不应该返回error")
+		assertEqual(t, tt.code, result.Code, "应该返回最后一次的结果")
+		assertEqual(t, tt.attempts, atomic.LoadInt32(&attempts), fmt.Sprintf("返回码%d的请求次数", tt.code))
+	}
+
+	policy := &RetryPolicy{RetryableCodes: []int{50000}}
+	assertFalse(t, policy.IsRetryableCode(10005), "指定RetryableCodes时只按列表判断")
+}
+
+func TestDoRequest_NoRetryByDefault(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+	_, err := client.DoRequest("GET", "/user/count", nil)
+
+	assertError(t, err, "502应该返回错误")
+	assertEqual(t, int32(1), atomic.LoadInt32(&attempts), "MaxHTTPTryTime为1时不应重试")
+}
+
+func TestTokenManager_AuthRetry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"success","data":{"token":"retried_token"}}`))
+	}))
+	defer server.Close()
+
+	client := createRetryTestClient(server.URL, 2)
+	client.GetTokenManager().ClearToken()
+
+	token, err := client.GetToken()
+
+	assertNoError(t, err, "鉴权重试后应该成功")
+	assertEqual(t, "retried_token", token, "应该返回重试后获取的token")
+	assertEqual(t, int32(2), atomic.LoadInt32(&attempts), "鉴权应该请求2次")
+}
@@ -0,0 +1,170 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSecretWatchInterval 文件MasterSecret的默认检查间隔
+const defaultSecretWatchInterval = 30 * time.Second
+
+// SecretProvider MasterSecret提供者，每次鉴权签名时调用，
+// 返回新的MasterSecret后下一次刷新token即使用新值
+type SecretProvider interface {
+	MasterSecret(ctx context.Context) (string, error)
+}
+
+// SecretProviderFunc 函数形式的SecretProvider
+type SecretProviderFunc func(ctx context.Context) (string, error)
+
+// MasterSecret 获取MasterSecret
+func (f SecretProviderFunc) MasterSecret(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticSecretProvider 固定的MasterSecret
+type StaticSecretProvider string
+
+// MasterSecret 获取MasterSecret
+func (s StaticSecretProvider) MasterSecret(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", ErrMasterSecretRequired
+	}
+	return string(s), nil
+}
+
+// EnvSecretProvider 从环境变量读取MasterSecret，每次调用时重新读取
+type EnvSecretProvider struct {
+	Name string // 环境变量名
+}
+
+// NewEnvSecretProvider 创建环境变量MasterSecret提供者
+func NewEnvSecretProvider(name string) *EnvSecretProvider {
+	return &EnvSecretProvider{Name: name}
+}
+
+// MasterSecret 获取MasterSecret
+func (p *EnvSecretProvider) MasterSecret(ctx context.Context) (string, error) {
+	secret := strings.TrimSpace(os.Getenv(p.Name))
+	if secret == "" {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrMasterSecretRequired, p.Name)
+	}
+	return secret, nil
+}
+
+// FileSecretProvider 从文件读取MasterSecret，后台定期检查文件变化，
+// 适用于Kubernetes Secret挂载等场景。读取失败时继续使用上一次读取到的值
+type FileSecretProvider struct {
+	path string
+
+	mu      sync.RWMutex
+	secret  string
+	modTime time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewFileSecretProvider 创建文件MasterSecret提供者并启动后台检查，
+// interval为检查间隔，小于等于0时使用30秒。文件无法读取或内容为空时返回错误
+func NewFileSecretProvider(path string, interval time.Duration) (*FileSecretProvider, error) {
+	p := &FileSecretProvider{
+		path:   path,
+		stopCh: make(chan struct{}),
+	}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+
+	if interval <= 0 {
+		interval = defaultSecretWatchInterval
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.reload()
+			}
+		}
+	}()
+
+	return p, nil
+}
+
+// MasterSecret 获取MasterSecret
+func (p *FileSecretProvider) MasterSecret(ctx context.Context) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.secret, nil
+}
+
+// Stop 停止后台检查
+func (p *FileSecretProvider) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+	p.wg.Wait()
+}
+
+// reload 文件修改时间变化时重新读取
+func (p *FileSecretProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	p.mu.RLock()
+	unchanged := p.secret != "" && info.ModTime().Equal(p.modTime)
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read secret file: %w", err)
+	}
+	secret := string(bytes.TrimSpace(data))
+	if secret == "" {
+		return fmt.Errorf("%w: secret file %s is empty", ErrMasterSecretRequired, p.path)
+	}
+
+	p.mu.Lock()
+	p.secret = secret
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+	return nil
+}
+
+// masterSecret 获取签名使用的MasterSecret，配置了SecretProvider时优先使用
+func (c *Config) masterSecret(ctx context.Context) (string, error) {
+	if c.SecretProvider == nil {
+		return c.MasterSecret, nil
+	}
+	secret, err := c.SecretProvider.MasterSecret(ctx)
+	if err == nil && secret == "" {
+		err = ErrMasterSecretRequired
+	}
+	if err != nil {
+		if errors.Is(err, ErrMasterSecretRequired) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to get master secret: %w", err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,137 @@
+package getui
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSecretProviders(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := StaticSecretProvider("static_secret").MasterSecret(ctx)
+	assertNoError(t, err, "固定MasterSecret不应返回错误")
+	assertEqual(t, "static_secret", secret, "应返回固定的MasterSecret")
+
+	provider := NewEnvSecretProvider("GETUI_SECRET_PROVIDER_TEST")
+	_, err = provider.MasterSecret(ctx)
+	assertTrue(t, errors.Is(err, ErrMasterSecretRequired), "环境变量未设置时应返回错误")
+	t.Setenv("GETUI_SECRET_PROVIDER_TEST", "env_secret")
+	secret, err = provider.MasterSecret(ctx)
+	assertNoError(t, err, "读取环境变量不应返回错误")
+	assertEqual(t, "env_secret", secret, "应返回环境变量中的MasterSecret")
+}
+
+func TestFileSecretProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "master_secret")
+	_, err := NewFileSecretProvider(path, time.Millisecond)
+	assertError(t, err, "文件不存在时应返回错误")
+
+	os.WriteFile(path, []byte("secret_v1\n"), 0600)
+	provider, err := NewFileSecretProvider(path, 10*time.Millisecond)
+	assertNoError(t, err, "创建文件MasterSecret提供者不应返回错误")
+	defer provider.Stop()
+
+	secret, _ := provider.MasterSecret(context.Background())
+	assertEqual(t, "secret_v1", secret, "应去除文件内容两端的空白")
+
+	os.WriteFile(path, []byte("secret_v2"), 0600)
+	future := time.Now().Add(time.Second)
+	os.Chtimes(path, future, future)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if secret, _ = provider.MasterSecret(context.Background()); secret == "secret_v2" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertEqual(t, "secret_v2", secret, "文件变化后应读取新的MasterSecret")
+
+	os.Remove(path)
+	time.Sleep(30 * time.Millisecond)
+	secret, _ = provider.MasterSecret(context.Background())
+	assertEqual(t, "secret_v2", secret, "读取失败时应继续使用上一次的值")
+}
+
+func TestTokenManager_SecretRotation(t *testing.T) {
+	config := getTestConfig()
+	var mu sync.Mutex
+	var revoked []string
+	tokens := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "DELETE" {
+			revoked = append(revoked, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.Write([]byte(`{"code":0,"msg":"success"}`))
+			return
+		}
+
+		// 根据签名判断使用的MasterSecret
+		var auth AuthDTO
+		json.NewDecoder(r.Body).Decode(&auth)
+		for _, secret := range []string{"secret_v1", "secret_v2"} {
+			if auth.Sign == fmt.Sprintf("%x", sha256.Sum256([]byte(config.AppKey+auth.Timestamp+secret))) {
+				tokens++
+				fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"token":"%s_%d"}}`, secret, tokens)
+				return
+			}
+		}
+		w.Write([]byte(`{"code":10001,"msg":"sign error"}`))
+	}))
+	defer server.Close()
+
+	var secretMu sync.Mutex
+	current := "secret_v1"
+	config.Domain = server.URL
+	config.MasterSecret = ""
+	config.SecretProvider = SecretProviderFunc(func(ctx context.Context) (string, error) {
+		secretMu.Lock()
+		defer secretMu.Unlock()
+		return current, nil
+	})
+	assertNoError(t, config.Validate(), "配置了SecretProvider时可不设置MasterSecret")
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+
+	token, err := tokenManager.GetToken()
+	assertNoError(t, err, "获取token不应返回错误")
+	assertEqual(t, "secret_v1_1", token, "应使用当前的MasterSecret签名")
+
+	secretMu.Lock()
+	current = "secret_v2"
+	secretMu.Unlock()
+
+	token, _ = tokenManager.GetToken()
+	assertEqual(t, "secret_v1_1", token, "token未过期时继续使用")
+
+	token, err = tokenManager.RefreshToken(context.Background())
+	assertNoError(t, err, "刷新token不应返回错误")
+	assertEqual(t, "secret_v2_2", token, "刷新时应使用新的MasterSecret")
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := len(revoked) > 0
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assertEqual(t, 1, len(revoked), "旧token应被吊销")
+	assertEqual(t, "secret_v1_1", revoked[0], "应吊销使用旧MasterSecret获取的token")
+}
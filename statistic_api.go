@@ -1,6 +1,7 @@
 package getui
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -11,7 +12,12 @@ type StatisticAPI struct {
 }
 
 // QueryPushResultByTaskIDs 根据任务ID查询推送结果
-func (api *StatisticAPI) QueryPushResultByTaskIDs(taskIDs []string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryPushResultByTaskIDs(taskIDs []string) (map[string]*PushReport, error) {
+	return api.QueryPushResultByTaskIDsContext(context.Background(), taskIDs)
+}
+
+// QueryPushResultByTaskIDsContext 根据任务ID查询推送结果（支持context）
+func (api *StatisticAPI) QueryPushResultByTaskIDsContext(ctx context.Context, taskIDs []string) (map[string]*PushReport, error) {
 	if len(taskIDs) == 0 {
 		return nil, fmt.Errorf("task_ids cannot be empty")
 	}
@@ -20,61 +26,112 @@ func (api *StatisticAPI) QueryPushResultByTaskIDs(taskIDs []string) (*ApiResult,
 		"task_id_list": taskIDs,
 	}
 
-	return api.client.DoRequest("POST", "/report/push/result", requestBody)
+	reports, err := Call[map[string]*PushReport](ctx, api.client, "POST", "/report/push/result", requestBody)
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
 }
 
 // QueryPushResultByDate 根据日期查询推送结果
-func (api *StatisticAPI) QueryPushResultByDate(date string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryPushResultByDate(date string) (*PushReport, error) {
+	return api.QueryPushResultByDateContext(context.Background(), date)
+}
+
+// QueryPushResultByDateContext 根据日期查询推送结果（支持context）
+func (api *StatisticAPI) QueryPushResultByDateContext(ctx context.Context, date string) (*PushReport, error) {
 	if date == "" {
 		// 默认查询今天的日期
 		date = time.Now().Format("2006-01-02")
 	}
 
 	url := fmt.Sprintf("/report/push/date/%s", date)
-	return api.client.DoRequest("GET", url, nil)
+	return api.queryPushReport(ctx, url, date)
 }
 
 // QueryPushResultByTaskID 根据单个任务ID查询推送结果
-func (api *StatisticAPI) QueryPushResultByTaskID(taskID string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryPushResultByTaskID(taskID string) (*PushReport, error) {
+	return api.QueryPushResultByTaskIDContext(context.Background(), taskID)
+}
+
+// QueryPushResultByTaskIDContext 根据单个任务ID查询推送结果（支持context）
+func (api *StatisticAPI) QueryPushResultByTaskIDContext(ctx context.Context, taskID string) (*PushReport, error) {
 	if taskID == "" {
 		return nil, fmt.Errorf("task_id cannot be empty")
 	}
 
 	url := fmt.Sprintf("/report/push/task/%s", taskID)
-	return api.client.DoRequest("GET", url, nil)
+	return api.queryPushReport(ctx, url, taskID)
 }
 
 // QueryUserData 查询用户数据
-func (api *StatisticAPI) QueryUserData(date string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryUserData(date string) (*UserReport, error) {
+	return api.QueryUserDataContext(context.Background(), date)
+}
+
+// QueryUserDataContext 查询用户数据（支持context）
+func (api *StatisticAPI) QueryUserDataContext(ctx context.Context, date string) (*UserReport, error) {
 	if date == "" {
 		date = time.Now().Format("2006-01-02")
 	}
 
 	url := fmt.Sprintf("/report/user/date/%s", date)
-	return api.client.DoRequest("GET", url, nil)
+	reports, err := Call[map[string]*UserReport](ctx, api.client, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return pickReport(reports, date)
 }
 
 // QueryPerformanceData 查询性能数据
-func (api *StatisticAPI) QueryPerformanceData(date string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryPerformanceData(date string) (*PushReport, error) {
+	return api.QueryPerformanceDataContext(context.Background(), date)
+}
+
+// QueryPerformanceDataContext 查询性能数据（支持context）
+func (api *StatisticAPI) QueryPerformanceDataContext(ctx context.Context, date string) (*PushReport, error) {
 	if date == "" {
 		date = time.Now().Format("2006-01-02")
 	}
 
 	url := fmt.Sprintf("/report/performance/date/%s", date)
-	return api.client.DoRequest("GET", url, nil)
+	return api.queryPushReport(ctx, url, date)
 }
 
 // QueryOnlineUserCount 查询在线用户数
-func (api *StatisticAPI) QueryOnlineUserCount() (*ApiResult, error) {
-	return api.client.DoRequest("GET", "/report/online_user", nil)
+func (api *StatisticAPI) QueryOnlineUserCount() (*OnlineUserReport, error) {
+	return api.QueryOnlineUserCountContext(context.Background())
+}
+
+// QueryOnlineUserCountContext 查询在线用户数（支持context）
+func (api *StatisticAPI) QueryOnlineUserCountContext(ctx context.Context) (*OnlineUserReport, error) {
+	report, err := Call[OnlineUserReport](ctx, api.client, "GET", "/report/online_user", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
 }
 
 // QueryAppData 查询应用数据
-func (api *StatisticAPI) QueryAppData(date string) (*ApiResult, error) {
+func (api *StatisticAPI) QueryAppData(date string) (*PushReport, error) {
+	return api.QueryAppDataContext(context.Background(), date)
+}
+
+// QueryAppDataContext 查询应用数据（支持context）
+func (api *StatisticAPI) QueryAppDataContext(ctx context.Context, date string) (*PushReport, error) {
 	if date == "" {
 		date = time.Now().Format("2006-01-02")
 	}
 
 	url := fmt.Sprintf("/report/app/date/%s", date)
-	return api.client.DoRequest("GET", url, nil)
+	return api.queryPushReport(ctx, url, date)
+}
+
+// queryPushReport 查询以日期或任务ID为键的推送报表
+func (api *StatisticAPI) queryPushReport(ctx context.Context, url, key string) (*PushReport, error) {
+	reports, err := Call[map[string]*PushReport](ctx, api.client, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return pickReport(reports, key)
 }
@@ -0,0 +1,202 @@
+package getui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 推送报表中的通道名称
+const (
+	ReportChannelTotal = "total" // 全部通道合计
+	ReportChannelGT    = "gt"    // 个推通道
+)
+
+// reportVendors 推送报表中的厂商通道名称
+var reportVendors = map[string]bool{
+	"hw": true, "ho": true, "xm": true, "op": true, "vv": true,
+	"mz": true, "st": true, "fcm": true, "apn": true, "ios": true,
+}
+
+// PushCount 推送数量统计
+type PushCount struct {
+	MsgNum     int64 `json:"msg_num"`     // 下发消息数
+	TargetNum  int64 `json:"target_num"`  // 目标用户数
+	ReceiveNum int64 `json:"receive_num"` // 到达数
+	DisplayNum int64 `json:"display_num"` // 展示数
+	ClickNum   int64 `json:"click_num"`   // 点击数
+}
+
+// Add 累加推送数量
+func (c PushCount) Add(other PushCount) PushCount {
+	return PushCount{
+		MsgNum:     c.MsgNum + other.MsgNum,
+		TargetNum:  c.TargetNum + other.TargetNum,
+		ReceiveNum: c.ReceiveNum + other.ReceiveNum,
+		DisplayNum: c.DisplayNum + other.DisplayNum,
+		ClickNum:   c.ClickNum + other.ClickNum,
+	}
+}
+
+// ReceiveRate 到达率，没有下发消息时为0
+func (c PushCount) ReceiveRate() float64 {
+	return ratio(c.ReceiveNum, c.MsgNum)
+}
+
+// ClickRate 点击率（点击数/展示数），没有展示时为0
+func (c PushCount) ClickRate() float64 {
+	return ratio(c.ClickNum, c.DisplayNum)
+}
+
+// PushReport 推送报表，包含合计、个推通道和各厂商通道的数量。
+// 对应数据格式为{"total": {...}, "gt": {...}, "hw": {...}, "xm": {...}, ...}
+type PushReport struct {
+	Total   PushCount                  // 全部通道合计
+	GT      PushCount                  // 个推通道
+	Vendors map[string]PushCount       // 厂商通道，如hw、ho、xm、op、vv、mz、st、fcm、apn
+	Extra   map[string]json.RawMessage // 不是通道数量的其他字段，如actionCntMap
+}
+
+// VendorTotal 所有厂商通道的合计
+func (r *PushReport) VendorTotal() PushCount {
+	var total PushCount
+	for _, count := range r.Vendors {
+		total = total.Add(count)
+	}
+	return total
+}
+
+// Channel 获取指定通道的数量，name可以是total、gt或厂商通道名称
+func (r *PushReport) Channel(name string) PushCount {
+	switch name {
+	case ReportChannelTotal:
+		return r.Total
+	case ReportChannelGT:
+		return r.GT
+	default:
+		return r.Vendors[name]
+	}
+}
+
+// UnmarshalJSON 解析推送报表，total、gt和已知厂商通道之外的字段保留在Extra中
+func (r *PushReport) UnmarshalJSON(data []byte) error {
+	var channels map[string]json.RawMessage
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return err
+	}
+
+	*r = PushReport{Vendors: make(map[string]PushCount)}
+	for name, raw := range channels {
+		if name != ReportChannelTotal && name != ReportChannelGT && !reportVendors[name] {
+			if r.Extra == nil {
+				r.Extra = make(map[string]json.RawMessage)
+			}
+			r.Extra[name] = raw
+			continue
+		}
+
+		var count PushCount
+		if err := json.Unmarshal(raw, &count); err != nil {
+			return fmt.Errorf("failed to decode channel %s: %w", name, err)
+		}
+		switch name {
+		case ReportChannelTotal:
+			r.Total = count
+		case ReportChannelGT:
+			r.GT = count
+		default:
+			r.Vendors[name] = count
+		}
+	}
+	return nil
+}
+
+// MarshalJSON 按接口格式序列化推送报表
+func (r PushReport) MarshalJSON() ([]byte, error) {
+	channels := make(map[string]interface{}, len(r.Vendors)+len(r.Extra)+2)
+	for name, raw := range r.Extra {
+		channels[name] = raw
+	}
+	for name, count := range r.Vendors {
+		channels[name] = count
+	}
+	channels[ReportChannelTotal] = r.Total
+	channels[ReportChannelGT] = r.GT
+	return json.Marshal(channels)
+}
+
+// UserReport 用户报表
+type UserReport struct {
+	AccumulativeNum int64 `json:"accumulative_num"` // 累计用户数
+	RegisterNum     int64 `json:"register_num"`     // 新增用户数
+	ActiveNum       int64 `json:"active_num"`       // 活跃用户数
+	OnlineNum       int64 `json:"online_num"`       // 在线用户数
+}
+
+// OnlineUserPoint 在线用户数时间点
+type OnlineUserPoint struct {
+	Time  time.Time
+	Count int64
+}
+
+// OnlineUserReport 在线用户数时间序列，按时间升序排列。
+// 对应数据格式为{"online_statics": {"$timestamp_ms": $count}}
+type OnlineUserReport struct {
+	Points []OnlineUserPoint
+}
+
+// Latest 获取最近一个时间点，没有数据时返回零值
+func (r *OnlineUserReport) Latest() OnlineUserPoint {
+	if len(r.Points) == 0 {
+		return OnlineUserPoint{}
+	}
+	return r.Points[len(r.Points)-1]
+}
+
+// UnmarshalJSON 解析在线用户数
+func (r *OnlineUserReport) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		OnlineStatics map[string]int64 `json:"online_statics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Points = make([]OnlineUserPoint, 0, len(raw.OnlineStatics))
+	for ts, count := range raw.OnlineStatics {
+		ms, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid online_statics timestamp %q", ts)
+		}
+		r.Points = append(r.Points, OnlineUserPoint{Time: time.UnixMilli(ms), Count: count})
+	}
+	sort.Slice(r.Points, func(i, j int) bool {
+		return r.Points[i].Time.Before(r.Points[j].Time)
+	})
+	return nil
+}
+
+// pickReport 获取以日期或任务ID为键的报表，只有一项时直接返回该项
+func pickReport[T any](reports map[string]*T, key string) (*T, error) {
+	if report, ok := reports[key]; ok && report != nil {
+		return report, nil
+	}
+	if len(reports) == 1 {
+		for _, report := range reports {
+			if report != nil {
+				return report, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no report for %s", ErrInvalidResponse, key)
+}
+
+// ratio 计算比例，分母为0时返回0
+func ratio(numerator, denominator int64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
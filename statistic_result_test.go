@@ -0,0 +1,86 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatisticAPI_PushReport(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"RASA_123":{
+		"total":{"msg_num":300,"target_num":200,"receive_num":150,"display_num":100,"click_num":20},
+		"gt":{"msg_num":100,"receive_num":80,"display_num":60,"click_num":10},
+		"hw":{"msg_num":120,"receive_num":50,"display_num":30,"click_num":6},
+		"xm":{"msg_num":80,"receive_num":20,"display_num":10,"click_num":4}
+	}}}`)
+
+	report, err := client.StatisticAPI.QueryPushResultByTaskID("RASA_123")
+	assertNoError(t, err, "查询推送结果不应返回错误")
+	assertEqual(t, int64(300), report.Total.MsgNum, "应解析合计")
+	assertEqual(t, int64(80), report.GT.ReceiveNum, "应解析个推通道")
+	assertEqual(t, 2, len(report.Vendors), "应解析厂商通道")
+	assertEqual(t, int64(70), report.VendorTotal().ReceiveNum, "应合计厂商通道")
+	assertEqual(t, int64(30), report.Channel("hw").DisplayNum, "应按名称获取通道")
+	assertEqual(t, 0.5, report.Total.ReceiveRate(), "应计算到达率")
+	assertEqual(t, 0.2, report.Total.ClickRate(), "应计算点击率")
+
+	data, err := json.Marshal(report)
+	assertNoError(t, err, "序列化不应返回错误")
+	var decoded PushReport
+	assertNoError(t, json.Unmarshal(data, &decoded), "反序列化不应返回错误")
+	assertEqual(t, report.Channel("xm"), decoded.Channel("xm"), "序列化后应保持一致")
+}
+
+func TestPushReport_UnmarshalJSON(t *testing.T) {
+	var report PushReport
+	err := json.Unmarshal([]byte(`{
+		"total":{"msg_num":10},
+		"hw":{"msg_num":6},
+		"actionCntMap":{"a":1},
+		"unknown":"x"
+	}`), &report)
+	assertNoError(t, err, "未知字段不应导致解析失败")
+	assertEqual(t, 1, len(report.Vendors), "只有已知厂商通道应解析为厂商通道")
+	assertEqual(t, int64(6), report.Channel("hw").MsgNum, "应解析厂商通道")
+	assertEqual(t, 2, len(report.Extra), "未知字段应保留在Extra中")
+	assertEqual(t, `{"a":1}`, string(report.Extra["actionCntMap"]), "应保留原始内容")
+
+	data, err := json.Marshal(report)
+	assertNoError(t, err, "序列化不应返回错误")
+	var decoded PushReport
+	assertNoError(t, json.Unmarshal(data, &decoded), "反序列化不应返回错误")
+	assertEqual(t, report.Extra, decoded.Extra, "序列化后应保留Extra")
+
+	err = json.Unmarshal([]byte(`{"total":{"msg_num":"ten"}}`), &report)
+	assertError(t, err, "通道数量格式错误时应返回错误")
+}
+
+func TestStatisticAPI_UserAndOnlineReport(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"2024-01-02":
+		{"accumulative_num":1000,"register_num":10,"active_num":300,"online_num":50}}}`)
+	userReport, err := client.StatisticAPI.QueryUserData("2024-01-02")
+	assertNoError(t, err, "查询用户数据不应返回错误")
+	assertEqual(t, int64(1000), userReport.AccumulativeNum, "应解析累计用户数")
+	assertEqual(t, int64(300), userReport.ActiveNum, "应解析活跃用户数")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{"online_statics":
+		{"1704168000000":120,"1704164400000":100}}}`)
+	onlineReport, err := client.StatisticAPI.QueryOnlineUserCount()
+	assertNoError(t, err, "查询在线用户数不应返回错误")
+	assertEqual(t, 2, len(onlineReport.Points), "应解析所有时间点")
+	assertEqual(t, int64(120), onlineReport.Latest().Count, "时间点应按时间排序")
+	assertTrue(t, onlineReport.Latest().Time.Equal(time.UnixMilli(1704168000000)), "应解析时间戳")
+}
+
+func TestStatisticAPI_Error(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":20001,"msg":"appkey not exist"}`)
+	_, err := client.StatisticAPI.QueryPushResultByDate("2024-01-02")
+	var apiErr *APIError
+	assertTrue(t, errors.As(err, &apiErr), "返回码非0时应返回APIError")
+	assertEqual(t, 20001, apiErr.Code, "应保留返回码")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{}}`)
+	_, err = client.StatisticAPI.QueryAppData("2024-01-02")
+	assertTrue(t, errors.Is(err, ErrInvalidResponse), "缺少报表时应返回ErrInvalidResponse")
+}
@@ -0,0 +1,138 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 标签推送的条件类型
+const (
+	TagKeyPhoneType = "phone_type" // 手机类型，如android、ios
+	TagKeyRegion    = "region"     // 省市编码
+	TagKeyCustomTag = "custom_tag" // 用户标签
+	TagKeyPortrait  = "portrait"   // 个推用户画像
+)
+
+// 标签推送条件中values之间的关系
+const (
+	TagOptOr  = "or"  // 满足任意一个值
+	TagOptAnd = "and" // 同时满足所有值
+	TagOptNot = "not" // 不满足任何一个值
+)
+
+// TagField 标签推送的条件类型，通过Tag创建
+type TagField string
+
+// Tag 创建标签推送条件，key为phone_type、region、custom_tag或portrait：
+//
+//	filter := getui.Tag("region").In("11000000").And(getui.Tag("phone_type").Is("android"))
+//	pushDTO.Audience = filter.Audience()
+func Tag(key string) TagField {
+	return TagField(key)
+}
+
+// Is 等于指定值
+func (f TagField) Is(value string) TagFilter {
+	return f.condition(TagOptOr, value)
+}
+
+// In 等于任意一个值
+func (f TagField) In(values ...string) TagFilter {
+	return f.condition(TagOptOr, values...)
+}
+
+// HasAll 同时包含所有值，用于custom_tag等可以有多个值的条件
+func (f TagField) HasAll(values ...string) TagFilter {
+	return f.condition(TagOptAnd, values...)
+}
+
+// NotIn 不等于任何一个值
+func (f TagField) NotIn(values ...string) TagFilter {
+	return f.condition(TagOptNot, values...)
+}
+
+// condition 创建单个条件
+func (f TagField) condition(optType string, values ...string) TagFilter {
+	return TagFilter{{Key: string(f), Values: values, OptType: optType}}
+}
+
+// TagFilter 标签推送条件列表，条件之间是与的关系，序列化为[{"key", "values", "opt_type"}]
+type TagFilter []TagCondition
+
+// And 追加条件，要求同时满足
+func (f TagFilter) And(others ...TagFilter) TagFilter {
+	result := append(TagFilter(nil), f...)
+	for _, other := range others {
+		result = append(result, other...)
+	}
+	return result
+}
+
+// Audience 创建标签推送的受众
+func (f TagFilter) Audience() *Audience {
+	return ByTags(f...)
+}
+
+// Validate 校验条件类型、关系和取值
+func (f TagFilter) Validate() error {
+	if len(f) == 0 {
+		return fmt.Errorf("%w: tag conditions cannot be empty", ErrInvalidAudience)
+	}
+	for i, condition := range f {
+		if err := condition.Validate(); err != nil {
+			return fmt.Errorf("tag[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// String 返回可读的条件描述，用于审计日志，如region in [11000000] and phone_type = android
+func (f TagFilter) String() string {
+	descriptions := make([]string, len(f))
+	for i, condition := range f {
+		descriptions[i] = condition.String()
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+// Validate 校验条件类型、关系和取值
+func (c TagCondition) Validate() error {
+	switch c.Key {
+	case TagKeyPhoneType, TagKeyRegion, TagKeyCustomTag, TagKeyPortrait:
+	default:
+		return fmt.Errorf("%w: unknown tag key %q", ErrInvalidAudience, c.Key)
+	}
+
+	switch c.OptType {
+	case TagOptOr, TagOptAnd, TagOptNot:
+	default:
+		return fmt.Errorf("%w: unknown opt_type %q for %s", ErrInvalidAudience, c.OptType, c.Key)
+	}
+
+	if len(c.Values) == 0 {
+		return fmt.Errorf("%w: values of %s cannot be empty", ErrInvalidAudience, c.Key)
+	}
+	for _, value := range c.Values {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%w: values of %s cannot contain empty value", ErrInvalidAudience, c.Key)
+		}
+	}
+	return nil
+}
+
+// String 返回可读的条件描述
+func (c TagCondition) String() string {
+	values := "[" + strings.Join(c.Values, ", ") + "]"
+	switch {
+	case c.OptType == TagOptOr && len(c.Values) == 1:
+		return fmt.Sprintf("%s = %s", c.Key, c.Values[0])
+	case c.OptType == TagOptOr:
+		return fmt.Sprintf("%s in %s", c.Key, values)
+	case c.OptType == TagOptAnd:
+		return fmt.Sprintf("%s has all %s", c.Key, values)
+	case c.OptType == TagOptNot:
+		return fmt.Sprintf("%s not in %s", c.Key, values)
+	default:
+		return fmt.Sprintf("%s %s %s", c.Key, c.OptType, values)
+	}
+}
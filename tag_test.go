@@ -0,0 +1,64 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTagFilter(t *testing.T) {
+	filter := Tag(TagKeyRegion).In("11000000", "12000000").
+		And(Tag(TagKeyPhoneType).Is("android"), Tag(TagKeyCustomTag).NotIn("blacklist"))
+
+	assertNoError(t, filter.Validate(), "有效的条件不应返回错误")
+	assertEqual(t, "region in [11000000, 12000000] and phone_type = android and custom_tag not in [blacklist]",
+		filter.String(), "应返回可读的条件描述")
+
+	data, err := json.Marshal(filter.Audience())
+	assertNoError(t, err, "序列化不应返回错误")
+	assertEqual(t, `{"tag":[`+
+		`{"key":"region","values":["11000000","12000000"],"opt_type":"or"},`+
+		`{"key":"phone_type","values":["android"],"opt_type":"or"},`+
+		`{"key":"custom_tag","values":["blacklist"],"opt_type":"not"}]}`, string(data), "应序列化为接口格式")
+
+	vip := Tag(TagKeyCustomTag).HasAll("vip", "active")
+	combined := vip.And(Tag(TagKeyPortrait).Is("1001"))
+	assertEqual(t, 1, len(vip), "And不应修改原条件")
+	assertEqual(t, "custom_tag has all [vip, active] and portrait = 1001", combined.String(), "应描述and关系")
+}
+
+func TestTagFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter TagFilter
+	}{
+		{"空条件", TagFilter{}},
+		{"未知条件类型", Tag("city").Is("beijing")},
+		{"未知关系", TagFilter{{Key: TagKeyRegion, Values: []string{"11000000"}, OptType: "xor"}}},
+		{"缺少取值", Tag(TagKeyRegion).In()},
+		{"空取值", Tag(TagKeyCustomTag).In("vip", " ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTrue(t, errors.Is(tt.filter.Validate(), ErrInvalidAudience), "无效条件应返回ErrInvalidAudience")
+		})
+	}
+}
+
+func TestPushByTag_ValidatesConditions(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{"taskid":"RASA_1"}}`)
+
+	_, err := client.PushAPI.PushByTag(&PushDTO{
+		Audience:    Tag("city").Is("beijing").Audience(),
+		PushMessage: createTestPushMessage(),
+	})
+	assertTrue(t, errors.Is(err, ErrInvalidAudience), "无效的标签条件应在请求前返回错误")
+
+	result, err := client.PushAPI.PushByTag(&PushDTO{
+		Audience:    Tag(TagKeyPhoneType).Is("ios").Audience(),
+		PushMessage: createTestPushMessage(),
+	})
+	assertNoError(t, err, "有效的标签条件不应返回错误")
+	assertEqual(t, "RASA_1", result.TaskID(), "应返回任务ID")
+}
@@ -0,0 +1,73 @@
+package getui
+
+import (
+	"context"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// withRequestTimeout 为单次请求设置读取超时和从连接池获取连接的超时，
+// 超时通过请求的context生效，不会修改共享的http.Client
+func withRequestTimeout(ctx context.Context, config *Config, uri string) (context.Context, context.CancelFunc) {
+	var cancels []context.CancelFunc
+
+	if timeout := config.GetCustomSocketTimeout(uri); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+		cancels = append(cancels, cancel)
+	}
+
+	if config.ConnectionRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = withConnectionRequestTimeout(ctx, time.Duration(config.ConnectionRequestTimeout)*time.Millisecond)
+		cancels = append(cancels, cancel)
+	}
+
+	return ctx, func() {
+		for i := len(cancels) - 1; i >= 0; i-- {
+			cancels[i]()
+		}
+	}
+}
+
+// withConnectionRequestTimeout 限制等待连接池分配连接的时间：
+// 从开始获取连接计时，拿到空闲连接或开始新建连接时停止计时
+func withConnectionRequestTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	stop := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			mu.Lock()
+			defer mu.Unlock()
+			timer = time.AfterFunc(timeout, func() {
+				cancel(ErrConnectionRequestTimeout)
+			})
+		},
+		ConnectStart: func(string, string) { stop() },
+		GotConn:      func(httptrace.GotConnInfo) { stop() },
+	}
+
+	return httptrace.WithClientTrace(ctx, trace), func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// requestError 获取请求失败的原因，连接池获取连接超时时返回ErrConnectionRequestTimeout
+func requestError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause == ErrConnectionRequestTimeout {
+		return cause
+	}
+	return err
+}
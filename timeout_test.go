@@ -0,0 +1,90 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetCustomSocketTimeout_Pattern(t *testing.T) {
+	config := NewDefaultConfig()
+	config.URIToSocketTimeoutMap["/push/all"] = 60000
+	config.URIToSocketTimeoutMap["/task/{task_id}"] = 3000
+	config.URIToSocketTimeoutMap["/task/schedule/{task_id}"] = 4000
+	config.URIToSocketTimeoutMap["/task/{kind}/{task_id}"] = 5000
+	config.URIToSocketTimeoutMap["/user/list"] = 2000
+
+	assertEqual(t, 60000, config.GetCustomSocketTimeout("/push/all"), "精确路径应匹配")
+	assertEqual(t, 3000, config.GetCustomSocketTimeout("/task/RASA_123"), "参数模式应匹配")
+	assertEqual(t, 4000, config.GetCustomSocketTimeout("/task/schedule/RASA_123"), "参数较少的模式优先")
+	assertEqual(t, 5000, config.GetCustomSocketTimeout("/task/other/RASA_123"), "多参数模式应匹配")
+	assertEqual(t, 2000, config.GetCustomSocketTimeout("/user/list?page=1&size=10"), "应忽略查询参数")
+	assertEqual(t, config.SocketTimeout, config.GetCustomSocketTimeout("/push/single/cid"), "未配置时使用默认超时")
+}
+
+func TestDoRequest_PerURITimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.URIToSocketTimeoutMap = map[string]int{"/task/{task_id}": 50}
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+
+	// 并发请求不同URI，超时配置互不影响
+	var wg sync.WaitGroup
+	var slowErr, normalErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, slowErr = client.DoRequest("DELETE", "/task/RASA_123", nil)
+	}()
+	go func() {
+		defer wg.Done()
+		_, normalErr = client.DoRequest("GET", "/user/count", nil)
+	}()
+	wg.Wait()
+
+	assertTrue(t, errors.Is(slowErr, context.DeadlineExceeded), "配置了超时的URI应超时")
+	assertNoError(t, normalErr, "其他URI不应受影响")
+	assertEqual(t, time.Duration(0), client.httpClient.Timeout, "不应修改共享http.Client的超时")
+}
+
+func TestDoRequest_ConnectionRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+getTestConfig().AppID+"/user/count" {
+			<-release
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.ConnectionRequestTimeout = 50
+	client := NewClient(config)
+	client.GetTokenManager().SetToken("test_token", time.Now().Add(time.Hour))
+	client.httpClient.Transport.(*http.Transport).MaxConnsPerHost = 1
+
+	// 第一个请求占用唯一的连接
+	go client.DoRequest("GET", "/user/slow", nil)
+	time.Sleep(50 * time.Millisecond)
+
+	_, err := client.DoRequest("GET", "/user/count", nil)
+
+	assertTrue(t, errors.Is(err, ErrConnectionRequestTimeout), "等待连接超时应返回ErrConnectionRequestTimeout")
+}
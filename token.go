@@ -1,21 +1,57 @@
 package getui
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 )
 
-// TokenManager 令牌管理器
+const (
+	// tokenRefreshRetryInterval 后台刷新token失败后的重试间隔
+	tokenRefreshRetryInterval = 10 * time.Second
+	// defaultTokenLifetime 服务端未返回有效过期时间时使用的token有效期
+	defaultTokenLifetime = 24 * time.Hour
+	// defaultTokenExpireMargin 默认的token过期余量
+	defaultTokenExpireMargin = time.Hour
+	// clockSkewThreshold 本地与服务端时钟偏差超过该值时进行校正
+	clockSkewThreshold = 2 * time.Second
+	// tokenStoreLockTTL TokenStore锁的有效期，也是等待其他实例鉴权的最长时间
+	tokenStoreLockTTL = 30 * time.Second
+	// tokenStorePollInterval 等待其他实例鉴权时读取TokenStore的间隔
+	tokenStorePollInterval = 100 * time.Millisecond
+)
+
+// TokenManager 令牌管理器，并发安全，同一时刻只有一个鉴权请求在进行
 type TokenManager struct {
-	config          *Config
-	httpClient      *http.Client
+	config        *Config
+	httpClient    *http.Client
+	domainManager *DomainManager
+	healthMonitor *HealthMonitor
+
+	mu              sync.Mutex
 	token           string
 	tokenExpireTime time.Time
+	tokenSecret     string     // 获取当前token时签名使用的MasterSecret，用于发现密钥轮换
+	rejectedToken   string     // 最近一次被判定无效的token，不再从TokenStore中读取
+	refreshing      *tokenCall // 进行中的鉴权请求
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// tokenCall 一次进行中的鉴权请求，等待者共享其结果
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
 }
 
 // NewTokenManager 创建新的令牌管理器
@@ -23,19 +59,282 @@ func NewTokenManager(config *Config, httpClient *http.Client) *TokenManager {
 	return &TokenManager{
 		config:     config,
 		httpClient: httpClient,
+		stopCh:     make(chan struct{}),
 	}
 }
 
 // GetToken 获取认证token
 func (tm *TokenManager) GetToken() (string, error) {
+	return tm.GetTokenContext(context.Background())
+}
+
+// GetTokenContext 获取认证token（支持context）
+func (tm *TokenManager) GetTokenContext(ctx context.Context) (string, error) {
+	return tm.getToken(ctx, false)
+}
+
+// RefreshToken 强制重新鉴权获取新token，与进行中的鉴权请求合并
+func (tm *TokenManager) RefreshToken(ctx context.Context) (string, error) {
+	return tm.getToken(ctx, true)
+}
+
+// getToken 获取token，缓存无效或force为true时发起鉴权。
+// 鉴权请求不随单个调用方的ctx取消，调用方取消时只停止等待
+func (tm *TokenManager) getToken(ctx context.Context, force bool) (string, error) {
+	tm.mu.Lock()
 	// 检查token是否过期
-	if tm.token != "" && time.Now().Before(tm.tokenExpireTime) {
-		return tm.token, nil
+	if !force && tm.validLocked() {
+		token := tm.token
+		tm.mu.Unlock()
+		return token, nil
+	}
+	if err := ctx.Err(); err != nil {
+		tm.mu.Unlock()
+		return "", err
+	}
+
+	call := tm.refreshing
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		tm.refreshing = call
+		stale := []string{tm.rejectedToken}
+		if force {
+			stale = append(stale, tm.token)
+		}
+		go tm.refresh(context.WithoutCancel(ctx), call, stale)
+	}
+	tm.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refresh 获取新token并更新缓存，完成后通知所有等待者，stale中的token不会被复用。
+// MasterSecret已轮换时，在新token生效后吊销用旧MasterSecret获取的token
+func (tm *TokenManager) refresh(ctx context.Context, call *tokenCall, stale []string) {
+	token, expireTime, secret, err := tm.obtainToken(ctx, stale)
+
+	var rotated string
+	tm.mu.Lock()
+	if err == nil {
+		if secret != "" && tm.tokenSecret != "" && secret != tm.tokenSecret && tm.token != token {
+			rotated = tm.token
+		}
+		tm.token = token
+		tm.tokenExpireTime = expireTime
+		if secret != "" {
+			tm.tokenSecret = secret
+		}
+	}
+	tm.refreshing = nil
+	tm.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+
+	if rotated != "" {
+		policy := tm.config.GetRetryPolicy()
+		if err := policy.retry(ctx, func() (bool, error) {
+			return tm.revoke(ctx, policy, rotated)
+		}); err != nil {
+			tm.config.logf("failed to revoke token after master secret rotation: %v", err)
+		}
+	}
+}
+
+// obtainToken 获取新token及签名使用的MasterSecret（从TokenStore读取时为空）。
+// 配置了TokenStore时优先使用存储中的有效token，否则获取锁后鉴权并写回存储；
+// 锁被其他实例持有时等待其写入，存储不可用或等待超时时直接鉴权
+func (tm *TokenManager) obtainToken(ctx context.Context, stale []string) (string, time.Time, string, error) {
+	store := tm.config.TokenStore
+	if store == nil {
+		return tm.fetchToken(ctx)
+	}
+	key := tm.storeKey()
+
+	deadline := time.Now().Add(tokenStoreLockTTL)
+	for {
+		if stored := tm.loadStoredToken(ctx, store, key, stale); stored != nil {
+			return stored.Token, stored.ExpireTime, "", nil
+		}
+
+		unlock, err := store.Lock(ctx, key, tokenStoreLockTTL)
+		if err == nil {
+			defer unlock()
+
+			// 获取锁后再检查一次，其他实例可能刚刚写入
+			if stored := tm.loadStoredToken(ctx, store, key, stale); stored != nil {
+				return stored.Token, stored.ExpireTime, "", nil
+			}
+
+			token, expireTime, secret, err := tm.fetchToken(ctx)
+			if err != nil {
+				return "", time.Time{}, "", err
+			}
+			// 写入失败不影响本实例使用新token
+			if err := store.Set(ctx, key, &StoredToken{Token: token, ExpireTime: expireTime}); err != nil {
+				tm.config.logf("failed to save token to store: %v", err)
+			}
+			return token, expireTime, secret, nil
+		}
+
+		if !errors.Is(err, ErrTokenStoreLocked) || time.Now().After(deadline) {
+			return tm.fetchToken(ctx)
+		}
+
+		timer := time.NewTimer(tokenStorePollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", time.Time{}, "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// loadStoredToken 从TokenStore读取有效且不在stale中的token
+func (tm *TokenManager) loadStoredToken(ctx context.Context, store TokenStore, key string, stale []string) *StoredToken {
+	stored, err := store.Get(ctx, key)
+	if err != nil || !stored.Valid() {
+		return nil
+	}
+	for _, token := range stale {
+		if stored.Token == token {
+			return nil
+		}
+	}
+	return stored
+}
+
+// storeKey 获取TokenStore中的key
+func (tm *TokenManager) storeKey() string {
+	return "getui:token:" + tm.config.AppID
+}
+
+// fetchToken 按重试策略请求鉴权接口，返回新token、本地过期时间和签名使用的MasterSecret
+func (tm *TokenManager) fetchToken(ctx context.Context) (string, time.Time, string, error) {
+	policy := tm.config.GetRetryPolicy()
+	var result *authResult
+	err := policy.retry(ctx, func() (bool, error) {
+		var retryable bool
+		var attemptErr error
+		start := time.Now()
+		result, retryable, attemptErr = tm.auth(ctx, policy)
+		if ctx.Err() == nil {
+			tm.healthMonitor.Record("/auth", time.Since(start), attemptErr)
+		}
+		return retryable, attemptErr
+	})
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+	return result.Token, tm.localExpireTime(result, tm.config.now()), result.secret, nil
+}
+
+// localExpireTime 将服务端过期时间换算为本地过期时间：
+// 按响应Date头估算的时钟偏差校正，缺少或无法解析过期时间时使用默认有效期，并提前TokenExpireMargin视为过期。
+// 校正后已经过期的token立即视为过期，下次获取时重新鉴权
+func (tm *TokenManager) localExpireTime(result *authResult, now time.Time) time.Time {
+	lifetime := defaultTokenLifetime
+	if serverExpire, err := result.ExpireAt(); err == nil {
+		if skew := result.clockSkew; skew > clockSkewThreshold || skew < -clockSkewThreshold {
+			serverExpire = serverExpire.Add(-skew)
+		}
+		remaining := serverExpire.Sub(now)
+		if remaining <= 0 {
+			return now
+		}
+		if remaining < defaultTokenLifetime {
+			lifetime = remaining
+		}
+	}
+
+	margin := tm.config.TokenExpireMargin
+	if margin <= 0 {
+		margin = defaultTokenExpireMargin
 	}
+	if margin >= lifetime {
+		margin = lifetime / 2
+	}
+
+	return now.Add(lifetime - margin)
+}
+
+// StartAutoRefresh 启动后台刷新，在token过期前TokenAutoRefreshAhead主动续期，
+// 使请求路径不必等待鉴权
+func (tm *TokenManager) StartAutoRefresh() {
+	ahead := tm.config.TokenAutoRefreshAhead
+	if ahead <= 0 {
+		ahead = 10 * time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tm.wg.Add(1)
+	go func() {
+		defer tm.wg.Done()
+		defer cancel()
 
-	// 生成新的token
+		go func() {
+			select {
+			case <-tm.stopCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		var delay time.Duration
+		for {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			if _, err := tm.RefreshToken(ctx); err != nil {
+				if ctx.Err() == nil {
+					tm.config.logf("failed to refresh token: %v", err)
+				}
+				delay = tokenRefreshRetryInterval
+				continue
+			}
+
+			delay = tm.GetTokenExpireTime().Add(-ahead).Sub(tm.config.now())
+			if delay < tokenRefreshRetryInterval {
+				delay = tokenRefreshRetryInterval
+			}
+		}
+	}()
+}
+
+// StopAutoRefresh 停止后台刷新
+func (tm *TokenManager) StopAutoRefresh() {
+	tm.stopOnce.Do(func() {
+		close(tm.stopCh)
+	})
+	tm.wg.Wait()
+}
+
+// authResult 鉴权结果
+type authResult struct {
+	AuthResultDTO
+	clockSkew time.Duration // 服务端时钟相对本地时钟的偏差
+	secret    string        // 签名使用的MasterSecret
+}
+
+// auth 请求一次鉴权接口，返回鉴权结果以及失败时是否可以重试
+func (tm *TokenManager) auth(ctx context.Context, policy *RetryPolicy) (*authResult, bool, error) {
 	timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-	sign := tm.generateSign(timestamp)
+	sign, secret, err := tm.generateSign(ctx, timestamp)
+	if err != nil {
+		return nil, false, err
+	}
 
 	authDTO := &AuthDTO{
 		Sign:      sign,
@@ -43,79 +342,214 @@ func (tm *TokenManager) GetToken() (string, error) {
 		AppKey:    tm.config.AppKey,
 	}
 
-	url := fmt.Sprintf("%s/%s/auth", tm.config.Domain, tm.config.AppID)
+	domain := tm.domain()
+	url := fmt.Sprintf("%s/%s/auth", domain, tm.config.AppID)
 
 	body, err := json.Marshal(authDTO)
 	if err != nil {
-		return "", &NetworkError{Message: "failed to marshal auth request", Cause: err}
+		return nil, false, &NetworkError{Message: "failed to marshal auth request", Cause: err}
 	}
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(body)))
+	reqCtx, cancel := withRequestTimeout(ctx, tm.config, "/auth")
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return "", &NetworkError{Message: "failed to create auth request", Cause: err}
+		return nil, false, &NetworkError{Message: "failed to create auth request", Cause: err}
 	}
 
 	req.Header.Set("Content-Type", "application/json;charset=utf-8")
+	req.Header.Set("Accept-Encoding", "gzip")
 
+	start := time.Now()
 	resp, err := tm.httpClient.Do(req)
 	if err != nil {
-		return "", &NetworkError{Message: "failed to send auth request", Cause: err}
+		if ctx.Err() == nil {
+			tm.reportDomain(domain, false)
+		}
+		err = &NetworkError{Message: "failed to send auth request", Cause: requestError(reqCtx, err)}
+		return nil, IsRetryable(err), err
 	}
 	defer resp.Body.Close()
+	tm.reportDomain(domain, !isRetryableStatus(resp.StatusCode))
 
-	var result ApiResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", &NetworkError{Message: "failed to decode auth response", Cause: err}
+	result, err := readResult(resp, tm.config.MaxResponseBodySize, tm.config.now())
+	if err != nil {
+		return nil, IsRetryable(err), err
 	}
 
 	if !result.IsSuccess() {
-		return "", &APIError{Code: result.Code, Message: result.Msg}
+		retryable := isRetryableStatus(resp.StatusCode) || policy.IsRetryableCode(result.Code)
+		return nil, retryable, &APIError{Code: result.Code, Message: result.Msg}
 	}
 
 	// 解析token
-	var tokenData struct {
-		Token string `json:"token"`
+	authRes := &authResult{secret: secret}
+	if err := json.Unmarshal(result.Data, &authRes.AuthResultDTO); err != nil {
+		return nil, false, &NetworkError{Message: "failed to parse token", Cause: err}
+	}
+
+	// 根据响应Date头估算服务端时钟偏差
+	if serverTime, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		end := time.Now()
+		localTime := start.Add(end.Sub(start) / 2)
+		authRes.clockSkew = serverTime.Sub(localTime)
 	}
-	if err := json.Unmarshal(result.Data, &tokenData); err != nil {
-		return "", &NetworkError{Message: "failed to parse token", Cause: err}
+
+	return authRes, false, nil
+}
+
+// RevokeToken 调用DELETE /auth/{token}使当前token在服务端失效，并清除本地缓存；
+// 配置了TokenStore且存储的是同一个token时一并清除
+func (tm *TokenManager) RevokeToken(ctx context.Context) error {
+	tm.mu.Lock()
+	token := tm.token
+	if token != "" {
+		tm.rejectedToken = token
+		tm.token = ""
+		tm.tokenExpireTime = time.Time{}
 	}
+	tm.mu.Unlock()
 
-	tm.token = tokenData.Token
-	tm.tokenExpireTime = time.Now().Add(23 * time.Hour) // token有效期24小时，提前1小时刷新
+	if token == "" {
+		return nil
+	}
+
+	if store := tm.config.TokenStore; store != nil {
+		if stored, err := store.Get(ctx, tm.storeKey()); err == nil && stored != nil && stored.Token == token {
+			_ = store.Set(ctx, tm.storeKey(), &StoredToken{})
+		}
+	}
 
-	return tm.token, nil
+	policy := tm.config.GetRetryPolicy()
+	return policy.retry(ctx, func() (bool, error) {
+		return tm.revoke(ctx, policy, token)
+	})
 }
 
-// generateSign 生成签名
-func (tm *TokenManager) generateSign(timestamp string) string {
+// revoke 请求一次吊销token接口，返回失败时是否可以重试
+func (tm *TokenManager) revoke(ctx context.Context, policy *RetryPolicy, token string) (bool, error) {
+	domain := tm.domain()
+	url := fmt.Sprintf("%s/%s/auth/%s", domain, tm.config.AppID, token)
+
+	reqCtx, cancel := withRequestTimeout(ctx, tm.config, "/auth/{token}")
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "DELETE", url, nil)
+	if err != nil {
+		return false, &NetworkError{Message: "failed to create revoke request", Cause: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json;charset=utf-8")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("token", token)
+
+	resp, err := tm.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			tm.reportDomain(domain, false)
+		}
+		err = &NetworkError{Message: "failed to send revoke request", Cause: requestError(reqCtx, err)}
+		return IsRetryable(err), err
+	}
+	defer resp.Body.Close()
+	tm.reportDomain(domain, !isRetryableStatus(resp.StatusCode))
+
+	result, err := readResult(resp, tm.config.MaxResponseBodySize, tm.config.now())
+	if err != nil {
+		return IsRetryable(err), err
+	}
+
+	if !result.IsSuccess() {
+		retryable := isRetryableStatus(resp.StatusCode) || policy.IsRetryableCode(result.Code)
+		return retryable, &APIError{Code: result.Code, Message: result.Msg}
+	}
+
+	return false, nil
+}
+
+// domain 获取鉴权使用的域名，未关联域名管理器时使用配置的Domain
+func (tm *TokenManager) domain() string {
+	if tm.domainManager != nil {
+		return tm.domainManager.CurrentDomain()
+	}
+	return tm.config.Domain
+}
+
+// reportDomain 向域名管理器反馈鉴权请求结果
+func (tm *TokenManager) reportDomain(domain string, ok bool) {
+	if tm.domainManager == nil {
+		return
+	}
+	if ok {
+		tm.domainManager.ReportSuccess(domain)
+	} else {
+		tm.domainManager.ReportFailure(domain)
+	}
+}
+
+// generateSign 生成签名，同时返回签名使用的MasterSecret
+func (tm *TokenManager) generateSign(ctx context.Context, timestamp string) (string, string, error) {
+	secret, err := tm.config.masterSecret(ctx)
+	if err != nil {
+		return "", "", err
+	}
 	// 签名算法：SHA256(appkey + timestamp + master_secret)
-	signStr := tm.config.AppKey + timestamp + tm.config.MasterSecret
-	return fmt.Sprintf("%x", sha256.Sum256([]byte(signStr)))
+	signStr := tm.config.AppKey + timestamp + secret
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(signStr))), secret, nil
+}
+
+// InvalidateToken 使指定token失效，仅当缓存的仍是该token时才清除，
+// 避免并发请求清除其他请求刚刷新的token
+func (tm *TokenManager) InvalidateToken(token string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.rejectedToken = token
+	if tm.token == token {
+		tm.token = ""
+		tm.tokenExpireTime = time.Time{}
+	}
 }
 
 // GetTokenExpireTime 获取token过期时间
 func (tm *TokenManager) GetTokenExpireTime() time.Time {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	return tm.tokenExpireTime
 }
 
 // GetCurrentToken 获取当前token（不检查过期）
 func (tm *TokenManager) GetCurrentToken() string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	return tm.token
 }
 
 // SetToken 设置token（用于测试）
 func (tm *TokenManager) SetToken(token string, expireTime time.Time) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	tm.token = token
 	tm.tokenExpireTime = expireTime
+	tm.tokenSecret = ""
 }
 
 // ClearToken 清除token（用于测试）
 func (tm *TokenManager) ClearToken() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	tm.token = ""
 	tm.tokenExpireTime = time.Time{}
 }
 
 // IsTokenExpired 检查token是否过期
 func (tm *TokenManager) IsTokenExpired() bool {
-	return tm.token == "" || time.Now().After(tm.tokenExpireTime)
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return !tm.validLocked()
+}
+
+// validLocked 判断缓存的token是否有效，调用方需持有锁
+func (tm *TokenManager) validLocked() bool {
+	return tm.token != "" && tm.config.now().Before(tm.tokenExpireTime)
 }
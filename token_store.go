@@ -0,0 +1,196 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenStore token存储，多个实例使用同一个存储即可共享一个个推token。
+// 可基于Redis、etcd等实现，Lock在锁被其他持有者占用时应返回ErrTokenStoreLocked
+type TokenStore interface {
+	// Get 获取token，不存在时返回nil
+	Get(ctx context.Context, key string) (*StoredToken, error)
+	// Set 保存token
+	Set(ctx context.Context, key string, token *StoredToken) error
+	// Lock 尝试获取锁，ttl后锁自动失效，返回的unlock用于释放锁
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func() error, err error)
+}
+
+// StoredToken 存储的token
+type StoredToken struct {
+	Token      string    `json:"token"`
+	ExpireTime time.Time `json:"expire_time"` // 本地过期时间，已扣除过期余量
+}
+
+// Valid 判断token是否有效
+func (t *StoredToken) Valid() bool {
+	return t != nil && t.Token != "" && time.Now().Before(t.ExpireTime)
+}
+
+// MemoryTokenStore 内存token存储，适用于同一进程内的多个客户端共享token
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*StoredToken
+	locks  map[string]time.Time
+}
+
+// NewMemoryTokenStore 创建内存token存储
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: make(map[string]*StoredToken),
+		locks:  make(map[string]time.Time),
+	}
+}
+
+// Get 获取token
+func (s *MemoryTokenStore) Get(ctx context.Context, key string) (*StoredToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *token
+	return &copied, nil
+}
+
+// Set 保存token
+func (s *MemoryTokenStore) Set(ctx context.Context, key string, token *StoredToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *token
+	s.tokens[key] = &copied
+	return nil
+}
+
+// Lock 尝试获取锁
+func (s *MemoryTokenStore) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expire, ok := s.locks[key]; ok && now.Before(expire) {
+		return nil, ErrTokenStoreLocked
+	}
+	expire := now.Add(ttl)
+	s.locks[key] = expire
+
+	return func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.locks[key] == expire {
+			delete(s.locks, key)
+		}
+		return nil
+	}, nil
+}
+
+// FileTokenStore 文件token存储，适用于同一台机器或共享文件系统上的多个实例。
+// token保存在dir/<key>.json，锁文件为dir/<key>.lock
+type FileTokenStore struct {
+	dir string
+}
+
+// NewFileTokenStore 创建文件token存储，目录不存在时自动创建
+func NewFileTokenStore(dir string) (*FileTokenStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create token store dir: %w", err)
+	}
+	return &FileTokenStore{dir: dir}, nil
+}
+
+// Get 获取token
+func (s *FileTokenStore) Get(ctx context.Context, key string) (*StoredToken, error) {
+	data, err := os.ReadFile(s.path(key, ".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var token StoredToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token file: %w", err)
+	}
+	return &token, nil
+}
+
+// Set 保存token，先写临时文件再重命名，保证读取方不会读到不完整的内容
+func (s *FileTokenStore) Set(ctx context.Context, key string, token *StoredToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, sanitizeStoreKey(key)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key, ".json")); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// Lock 尝试通过创建锁文件获取锁，锁文件超过ttl未释放时视为失效
+func (s *FileTokenStore) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	path := s.path(key, ".lock")
+
+	for i := 0; i < 2; i++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() error {
+				if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				return nil
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		// 清理过期的锁文件后重试一次
+		info, statErr := os.Stat(path)
+		if statErr != nil || time.Since(info.ModTime()) < ttl {
+			break
+		}
+		os.Remove(path)
+	}
+	return nil, ErrTokenStoreLocked
+}
+
+// path 获取key对应的文件路径
+func (s *FileTokenStore) path(key, ext string) string {
+	return filepath.Join(s.dir, sanitizeStoreKey(key)+ext)
+}
+
+// sanitizeStoreKey 将key转换为安全的文件名
+func sanitizeStoreKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, key)
+}
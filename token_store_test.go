@@ -0,0 +1,112 @@
+package getui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testTokenStore(t *testing.T, store TokenStore) {
+	ctx := context.Background()
+
+	stored, err := store.Get(ctx, "getui:token:app")
+	assertNoError(t, err, "读取不存在的token不应返回错误")
+	assertTrue(t, stored == nil, "不存在的token应返回nil")
+
+	expireTime := time.Now().Add(time.Hour).Round(0)
+	assertNoError(t, store.Set(ctx, "getui:token:app", &StoredToken{Token: "shared_token", ExpireTime: expireTime}), "保存token不应返回错误")
+
+	stored, err = store.Get(ctx, "getui:token:app")
+	assertNoError(t, err, "读取token不应返回错误")
+	assertEqual(t, "shared_token", stored.Token, "读取的token应匹配")
+	assertTrue(t, stored.ExpireTime.Equal(expireTime), "读取的过期时间应匹配")
+	assertTrue(t, stored.Valid(), "token应有效")
+
+	unlock, err := store.Lock(ctx, "getui:token:app", time.Minute)
+	assertNoError(t, err, "首次加锁应成功")
+
+	_, err = store.Lock(ctx, "getui:token:app", time.Minute)
+	assertEqual(t, ErrTokenStoreLocked, err, "锁被占用时应返回ErrTokenStoreLocked")
+
+	assertNoError(t, unlock(), "解锁不应返回错误")
+	unlock, err = store.Lock(ctx, "getui:token:app", time.Minute)
+	assertNoError(t, err, "解锁后应能再次加锁")
+	unlock()
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	testTokenStore(t, NewMemoryTokenStore())
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileTokenStore(filepath.Join(dir, "tokens"))
+	assertNoError(t, err, "创建文件存储不应返回错误")
+
+	testTokenStore(t, store)
+
+	_, err = os.Stat(filepath.Join(dir, "tokens", "getui_token_app.json"))
+	assertNoError(t, err, "token文件应存在")
+}
+
+func TestFileTokenStore_StaleLock(t *testing.T) {
+	store, err := NewFileTokenStore(t.TempDir())
+	assertNoError(t, err, "创建文件存储不应返回错误")
+
+	_, err = store.Lock(context.Background(), "key", time.Minute)
+	assertNoError(t, err, "首次加锁应成功")
+
+	lockPath := store.path("key", ".lock")
+	old := time.Now().Add(-2 * time.Minute)
+	os.Chtimes(lockPath, old, old)
+
+	unlock, err := store.Lock(context.Background(), "key", time.Minute)
+	assertNoError(t, err, "过期的锁应被清理")
+	unlock()
+}
+
+func TestTokenManager_SharedTokenStore(t *testing.T) {
+	var authCount int32
+	server := createAuthTestServer(&authCount, 50*time.Millisecond)
+	defer server.Close()
+
+	store := NewMemoryTokenStore()
+	var managers []*TokenManager
+	for i := 0; i < 5; i++ {
+		config := getTestConfig()
+		config.Domain = server.URL
+		config.TokenStore = store
+		managers = append(managers, NewTokenManager(config, config.GetHTTPClient()))
+	}
+
+	var wg sync.WaitGroup
+	tokens := make([]string, len(managers))
+	for i, tm := range managers {
+		wg.Add(1)
+		go func(i int, tm *TokenManager) {
+			defer wg.Done()
+			tokens[i], _ = tm.GetToken()
+		}(i, tm)
+	}
+	wg.Wait()
+
+	assertEqual(t, int32(1), atomic.LoadInt32(&authCount), "共享存储时所有实例只应鉴权一次")
+	for _, token := range tokens {
+		assertEqual(t, "token_1", token, "所有实例应使用同一个token")
+	}
+
+	// token被拒绝后不应再从存储中读取
+	managers[0].InvalidateToken("token_1")
+	token, err := managers[0].GetToken()
+	assertNoError(t, err, "重新获取token不应返回错误")
+	assertEqual(t, "token_2", token, "被拒绝的token不应复用")
+
+	token, err = managers[1].RefreshToken(context.Background())
+	assertNoError(t, err, "强制刷新不应返回错误")
+	assertEqual(t, "token_2", token, "其他实例刷新时应使用存储中的新token")
+	assertEqual(t, int32(2), atomic.LoadInt32(&authCount), "只应再鉴权一次")
+}
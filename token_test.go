@@ -1,8 +1,14 @@
 package getui
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -148,7 +154,8 @@ func TestTokenManager_SignGeneration(t *testing.T) {
 
 	// 测试签名生成
 	timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-	sign := tokenManager.generateSign(timestamp)
+	sign, _, err := tokenManager.generateSign(context.Background(), timestamp)
+	assertNoError(t, err, "生成签名不应返回错误")
 
 	// 验证签名不为空
 	assertStringNotEmpty(t, sign, "生成的签名不应该为空")
@@ -172,8 +179,8 @@ func TestTokenManager_SignConsistency(t *testing.T) {
 
 	// 使用相同的时间戳生成两次签名
 	timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-	sign1 := tokenManager.generateSign(timestamp)
-	sign2 := tokenManager.generateSign(timestamp)
+	sign1, _, _ := tokenManager.generateSign(context.Background(), timestamp)
+	sign2, _, _ := tokenManager.generateSign(context.Background(), timestamp)
 
 	// 验证签名一致性
 	assertEqual(t, sign1, sign2, "相同时间戳生成的签名应该一致")
@@ -259,7 +266,8 @@ func TestTokenManager_RequestFormat(t *testing.T) {
 
 	// 测试请求格式构建
 	timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-	sign := tokenManager.generateSign(timestamp)
+	sign, _, err := tokenManager.generateSign(context.Background(), timestamp)
+	assertNoError(t, err, "生成签名不应返回错误")
 
 	authDTO := &AuthDTO{
 		Sign:      sign,
@@ -473,3 +481,169 @@ func TestClient_GetToken_Delegation(t *testing.T) {
 		t.Error("Client和TokenManager的GetToken行为应该一致")
 	}
 }
+
+// 创建返回递增token的鉴权测试服务器
+func createAuthTestServer(authCount *int32, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(authCount, 1)
+		time.Sleep(delay)
+		fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"token":"token_%d"}}`, n)
+	}))
+}
+
+func TestTokenManager_SingleFlight(t *testing.T) {
+	var authCount int32
+	server := createAuthTestServer(&authCount, 50*time.Millisecond)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 100)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = tokenManager.GetToken()
+		}(i)
+	}
+	wg.Wait()
+
+	assertEqual(t, int32(1), atomic.LoadInt32(&authCount), "并发获取token只应鉴权一次")
+	for _, token := range tokens {
+		assertEqual(t, "token_1", token, "所有调用方应获取同一个token")
+	}
+
+	token, err := tokenManager.RefreshToken(context.Background())
+	assertNoError(t, err, "强制刷新不应返回错误")
+	assertEqual(t, "token_2", token, "强制刷新应获取新token")
+}
+
+func TestTokenManager_WaiterCanceled(t *testing.T) {
+	var authCount int32
+	server := createAuthTestServer(&authCount, 100*time.Millisecond)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := tokenManager.GetTokenContext(ctx)
+	assertEqual(t, context.DeadlineExceeded, err, "调用方超时应停止等待")
+
+	// 调用方取消不影响进行中的鉴权
+	token, err := tokenManager.GetToken()
+	assertNoError(t, err, "后续获取不应返回错误")
+	assertEqual(t, "token_1", token, "应复用进行中的鉴权结果")
+	assertEqual(t, int32(1), atomic.LoadInt32(&authCount), "只应鉴权一次")
+}
+
+func TestTokenManager_AutoRefresh(t *testing.T) {
+	var authCount int32
+	server := createAuthTestServer(&authCount, 0)
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+
+	tokenManager.StartAutoRefresh()
+	defer tokenManager.StopAutoRefresh()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for tokenManager.GetCurrentToken() == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assertEqual(t, "token_1", tokenManager.GetCurrentToken(), "后台刷新应主动获取token")
+
+	token, err := tokenManager.GetToken()
+	assertNoError(t, err, "获取token不应返回错误")
+	assertEqual(t, "token_1", token, "请求路径应直接使用后台获取的token")
+	assertEqual(t, int32(1), atomic.LoadInt32(&authCount), "请求路径不应再次鉴权")
+}
+
+func TestTokenManager_ServerExpireTime(t *testing.T) {
+	expireAt := time.Now().Add(2 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code":0,"msg":"success","data":{"token":"server_token","expire_time":"%d"}}`, expireAt.UnixMilli())
+	}))
+	defer server.Close()
+
+	config := getTestConfig()
+	config.Domain = server.URL
+	config.TokenExpireMargin = 10 * time.Minute
+	tokenManager := NewTokenManager(config, config.GetHTTPClient())
+
+	_, err := tokenManager.GetToken()
+	assertNoError(t, err, "获取token不应返回错误")
+
+	diff := tokenManager.GetTokenExpireTime().Sub(expireAt.Add(-10 * time.Minute))
+	assertTrue(t, diff > -time.Second && diff < time.Second, "本地过期时间应为服务端过期时间减去余量")
+}
+
+func TestTokenManager_LocalExpireTime(t *testing.T) {
+	config := NewDefaultConfig()
+	config.TokenExpireMargin = 10 * time.Minute
+	tokenManager := NewTokenManager(config, nil)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		result   *authResult
+		expected time.Time
+	}{
+		{
+			name:     "服务端过期时间",
+			result:   &authResult{AuthResultDTO: AuthResultDTO{ExpireTime: json.Number(strconv.FormatInt(now.Add(2*time.Hour).UnixMilli(), 10))}},
+			expected: now.Add(2*time.Hour - 10*time.Minute),
+		},
+		{
+			name: "服务端时钟快1小时",
+			result: &authResult{
+				AuthResultDTO: AuthResultDTO{ExpireTime: json.Number(strconv.FormatInt(now.Add(3*time.Hour).UnixMilli(), 10))},
+				clockSkew:     time.Hour,
+			},
+			expected: now.Add(2*time.Hour - 10*time.Minute),
+		},
+		{
+			name:     "过期时间早于本地时间",
+			result:   &authResult{AuthResultDTO: AuthResultDTO{ExpireTime: json.Number(strconv.FormatInt(now.Add(-time.Hour).UnixMilli(), 10))}},
+			expected: now,
+		},
+		{
+			name: "校正时钟偏差后已过期",
+			result: &authResult{
+				AuthResultDTO: AuthResultDTO{ExpireTime: json.Number(strconv.FormatInt(now.Add(30*time.Minute).UnixMilli(), 10))},
+				clockSkew:     time.Hour,
+			},
+			expected: now,
+		},
+		{
+			name:     "过期时间无法解析",
+			result:   &authResult{AuthResultDTO: AuthResultDTO{ExpireTime: json.Number("soon")}},
+			expected: now.Add(24*time.Hour - 10*time.Minute),
+		},
+		{
+			name:     "缺少过期时间",
+			result:   &authResult{},
+			expected: now.Add(24*time.Hour - 10*time.Minute),
+		},
+		{
+			name:     "有效期短于余量",
+			result:   &authResult{AuthResultDTO: AuthResultDTO{ExpireTime: json.Number(strconv.FormatInt(now.Add(10*time.Minute).UnixMilli(), 10))}},
+			expected: now.Add(5 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := tokenManager.localExpireTime(tt.result, now).Sub(tt.expected)
+			assertTrue(t, diff > -time.Millisecond && diff < time.Millisecond, "本地过期时间应匹配")
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package getui
+
+import (
+	"strings"
+)
+
+// endpointPatterns 带路径参数的接口模式，静态路径需排在同前缀的参数路径之前
+var endpointPatterns = []string{
+	"/user/alias/batch",
+	"/user/alias/{cid}",
+	"/user/cid/{alias}",
+	"/user/detail/{cid}",
+	"/user/tag/{cid}",
+	"/task/schedule/{task_id}",
+	"/task/{task_id}",
+	"/report/push/date/{date}",
+	"/report/push/task/{task_id}",
+	"/report/user/date/{date}",
+	"/report/performance/date/{date}",
+	"/report/app/date/{date}",
+}
+
+// stripQuery 去掉URI中的查询参数
+func stripQuery(uri string) string {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+// matchURIPattern 判断URI是否匹配模式，模式中的{name}匹配任意单个路径段
+func matchURIPattern(pattern, uri string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	uriSegments := strings.Split(strings.Trim(stripQuery(uri), "/"), "/")
+	if len(patternSegments) != len(uriSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if uriSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != uriSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// endpointOf 将URI归一化为接口模式，用于按接口聚合统计
+func endpointOf(uri string) string {
+	path := stripQuery(uri)
+	for _, pattern := range endpointPatterns {
+		if matchURIPattern(pattern, path) {
+			return pattern
+		}
+	}
+	return path
+}
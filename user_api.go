@@ -1,6 +1,7 @@
 package getui
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -10,38 +11,66 @@ type UserAPI struct {
 }
 
 // QueryUserStatus 查询用户状态
-func (api *UserAPI) QueryUserStatus(cids []string) (*ApiResult, error) {
+func (api *UserAPI) QueryUserStatus(cids []string) (map[string]*UserStatus, error) {
+	return api.QueryUserStatusContext(context.Background(), cids)
+}
+
+// QueryUserStatusContext 查询用户状态（支持context）
+func (api *UserAPI) QueryUserStatusContext(ctx context.Context, cids []string) (map[string]*UserStatus, error) {
 	if len(cids) == 0 {
 		return nil, fmt.Errorf("cids cannot be empty")
 	}
 
-	requestBody := map[string]interface{}{
-		"cid": cids,
+	statuses, err := Call[map[string]*UserStatus](ctx, api.client, "POST", "/user/status", &userStatusDTO{CIDs: cids})
+	if err != nil {
+		return nil, err
 	}
-
-	return api.client.DoRequest("POST", "/user/status", requestBody)
+	return statuses, nil
 }
 
 // QueryAliasByCID 根据CID查询别名
-func (api *UserAPI) QueryAliasByCID(cid string) (*ApiResult, error) {
+func (api *UserAPI) QueryAliasByCID(cid string) (string, error) {
+	return api.QueryAliasByCIDContext(context.Background(), cid)
+}
+
+// QueryAliasByCIDContext 根据CID查询别名（支持context）
+func (api *UserAPI) QueryAliasByCIDContext(ctx context.Context, cid string) (string, error) {
 	if cid == "" {
-		return nil, ErrInvalidCID
+		return "", ErrInvalidCID
 	}
 
-	return api.client.DoRequest("GET", fmt.Sprintf("/user/alias/%s", cid), nil)
+	alias, err := Call[aliasDTO](ctx, api.client, "GET", fmt.Sprintf("/user/alias/%s", cid), nil)
+	if err != nil {
+		return "", err
+	}
+	return alias.Alias, nil
 }
 
 // QueryCIDByAlias 根据别名查询CID
-func (api *UserAPI) QueryCIDByAlias(alias string) (*ApiResult, error) {
+func (api *UserAPI) QueryCIDByAlias(alias string) ([]string, error) {
+	return api.QueryCIDByAliasContext(context.Background(), alias)
+}
+
+// QueryCIDByAliasContext 根据别名查询CID（支持context）
+func (api *UserAPI) QueryCIDByAliasContext(ctx context.Context, alias string) ([]string, error) {
 	if alias == "" {
 		return nil, ErrInvalidAlias
 	}
 
-	return api.client.DoRequest("GET", fmt.Sprintf("/user/cid/%s", alias), nil)
+	cids, err := Call[cidListDTO](ctx, api.client, "GET", fmt.Sprintf("/user/cid/%s", alias), nil)
+	if err != nil {
+		return nil, err
+	}
+	return cids.CIDs, nil
 }
 
 // BindAlias 绑定别名
 func (api *UserAPI) BindAlias(alias string, cid string) (*ApiResult, error) {
+	return api.BindAliasContext(context.Background(), alias, cid)
+}
+
+// BindAliasContext 绑定别名（支持context）
+func (api *UserAPI) BindAliasContext(ctx context.Context, alias string, cid string) (*ApiResult, error) {
 	if alias == "" {
 		return nil, ErrInvalidAlias
 	}
@@ -49,16 +78,16 @@ func (api *UserAPI) BindAlias(alias string, cid string) (*ApiResult, error) {
 		return nil, ErrInvalidCID
 	}
 
-	requestBody := map[string]string{
-		"alias": alias,
-		"cid":   cid,
-	}
-
-	return api.client.DoRequest("POST", "/user/alias", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "POST", "/user/alias", &AliasBinding{CID: cid, Alias: alias}))
 }
 
 // UnbindAlias 解绑别名
 func (api *UserAPI) UnbindAlias(alias string, cid string) (*ApiResult, error) {
+	return api.UnbindAliasContext(context.Background(), alias, cid)
+}
+
+// UnbindAliasContext 解绑别名（支持context）
+func (api *UserAPI) UnbindAliasContext(ctx context.Context, alias string, cid string) (*ApiResult, error) {
 	if alias == "" {
 		return nil, ErrInvalidAlias
 	}
@@ -66,93 +95,125 @@ func (api *UserAPI) UnbindAlias(alias string, cid string) (*ApiResult, error) {
 		return nil, ErrInvalidCID
 	}
 
-	requestBody := map[string]string{
-		"alias": alias,
-		"cid":   cid,
-	}
-
-	return api.client.DoRequest("DELETE", "/user/alias", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "DELETE", "/user/alias", &AliasBinding{CID: cid, Alias: alias}))
 }
 
 // BindAliasBatch 批量绑定别名
-func (api *UserAPI) BindAliasBatch(aliasCidList []map[string]string) (*ApiResult, error) {
-	if len(aliasCidList) == 0 {
-		return nil, fmt.Errorf("alias_cid_list cannot be empty")
-	}
+func (api *UserAPI) BindAliasBatch(bindings []AliasBinding) (*ApiResult, error) {
+	return api.BindAliasBatchContext(context.Background(), bindings)
+}
 
-	requestBody := map[string]interface{}{
-		"data_list": aliasCidList,
+// BindAliasBatchContext 批量绑定别名（支持context）
+func (api *UserAPI) BindAliasBatchContext(ctx context.Context, bindings []AliasBinding) (*ApiResult, error) {
+	if err := validateAliasBindings(bindings); err != nil {
+		return nil, err
 	}
 
-	return api.client.DoRequest("POST", "/user/alias/batch", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "POST", "/user/alias/batch", &aliasBatchDTO{DataList: bindings}))
 }
 
 // UnbindAliasBatch 批量解绑别名
-func (api *UserAPI) UnbindAliasBatch(aliasCidList []map[string]string) (*ApiResult, error) {
-	if len(aliasCidList) == 0 {
-		return nil, fmt.Errorf("alias_cid_list cannot be empty")
-	}
+func (api *UserAPI) UnbindAliasBatch(bindings []AliasBinding) (*ApiResult, error) {
+	return api.UnbindAliasBatchContext(context.Background(), bindings)
+}
 
-	requestBody := map[string]interface{}{
-		"data_list": aliasCidList,
+// UnbindAliasBatchContext 批量解绑别名（支持context）
+func (api *UserAPI) UnbindAliasBatchContext(ctx context.Context, bindings []AliasBinding) (*ApiResult, error) {
+	if err := validateAliasBindings(bindings); err != nil {
+		return nil, err
 	}
 
-	return api.client.DoRequest("DELETE", "/user/alias/batch", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "DELETE", "/user/alias/batch", &aliasBatchDTO{DataList: bindings}))
 }
 
 // QueryUserDetail 查询用户详情
-func (api *UserAPI) QueryUserDetail(cid string) (*ApiResult, error) {
+func (api *UserAPI) QueryUserDetail(cid string) (*UserDetail, error) {
+	return api.QueryUserDetailContext(context.Background(), cid)
+}
+
+// QueryUserDetailContext 查询用户详情（支持context）
+func (api *UserAPI) QueryUserDetailContext(ctx context.Context, cid string) (*UserDetail, error) {
 	if cid == "" {
 		return nil, ErrInvalidCID
 	}
 
-	return api.client.DoRequest("GET", fmt.Sprintf("/user/detail/%s", cid), nil)
+	detail, err := Call[userDetailDTO](ctx, api.client, "GET", fmt.Sprintf("/user/detail/%s", cid), nil)
+	if err != nil {
+		return nil, err
+	}
+	if user := detail.ValidCIDs[cid]; user != nil {
+		return user, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, cid)
 }
 
 // SetUserTag 设置用户标签
 func (api *UserAPI) SetUserTag(cid string, tags []string) (*ApiResult, error) {
+	return api.SetUserTagContext(context.Background(), cid, tags)
+}
+
+// SetUserTagContext 设置用户标签（支持context）
+func (api *UserAPI) SetUserTagContext(ctx context.Context, cid string, tags []string) (*ApiResult, error) {
 	if cid == "" {
 		return nil, ErrInvalidCID
 	}
 
-	requestBody := map[string]interface{}{
-		"cid":  cid,
-		"tags": tags,
-	}
-
-	return api.client.DoRequest("POST", "/user/tag", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "POST", "/user/tag", &userTagDTO{CID: cid, Tags: tags}))
 }
 
 // GetUserTag 获取用户标签
-func (api *UserAPI) GetUserTag(cid string) (*ApiResult, error) {
+func (api *UserAPI) GetUserTag(cid string) ([]string, error) {
+	return api.GetUserTagContext(context.Background(), cid)
+}
+
+// GetUserTagContext 获取用户标签（支持context）
+func (api *UserAPI) GetUserTagContext(ctx context.Context, cid string) ([]string, error) {
 	if cid == "" {
 		return nil, ErrInvalidCID
 	}
 
-	return api.client.DoRequest("GET", fmt.Sprintf("/user/tag/%s", cid), nil)
+	tags, err := Call[map[string][]string](ctx, api.client, "GET", fmt.Sprintf("/user/tag/%s", cid), nil)
+	if err != nil {
+		return nil, err
+	}
+	return tags[cid], nil
 }
 
 // DeleteUserTag 删除用户标签
 func (api *UserAPI) DeleteUserTag(cid string, tags []string) (*ApiResult, error) {
+	return api.DeleteUserTagContext(context.Background(), cid, tags)
+}
+
+// DeleteUserTagContext 删除用户标签（支持context）
+func (api *UserAPI) DeleteUserTagContext(ctx context.Context, cid string, tags []string) (*ApiResult, error) {
 	if cid == "" {
 		return nil, ErrInvalidCID
 	}
 
-	requestBody := map[string]interface{}{
-		"cid":  cid,
-		"tags": tags,
-	}
-
-	return api.client.DoRequest("DELETE", "/user/tag", requestBody)
+	return checkResult(api.client.DoRequestContext(ctx, "DELETE", "/user/tag", &userTagDTO{CID: cid, Tags: tags}))
 }
 
 // GetUserCount 获取用户数量
-func (api *UserAPI) GetUserCount() (*ApiResult, error) {
-	return api.client.DoRequest("GET", "/user/count", nil)
+func (api *UserAPI) GetUserCount() (int64, error) {
+	return api.GetUserCountContext(context.Background())
+}
+
+// GetUserCountContext 获取用户数量（支持context）
+func (api *UserAPI) GetUserCountContext(ctx context.Context) (int64, error) {
+	count, err := Call[userCountDTO](ctx, api.client, "GET", "/user/count", nil)
+	if err != nil {
+		return 0, err
+	}
+	return count.UserCount, nil
 }
 
 // GetUserList 获取用户列表
 func (api *UserAPI) GetUserList(page int, size int) (*ApiResult, error) {
+	return api.GetUserListContext(context.Background(), page, size)
+}
+
+// GetUserListContext 获取用户列表（支持context）
+func (api *UserAPI) GetUserListContext(ctx context.Context, page int, size int) (*ApiResult, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -161,5 +222,21 @@ func (api *UserAPI) GetUserList(page int, size int) (*ApiResult, error) {
 	}
 
 	url := fmt.Sprintf("/user/list?page=%d&size=%d", page, size)
-	return api.client.DoRequest("GET", url, nil)
+	return checkResult(api.client.DoRequestContext(ctx, "GET", url, nil))
+}
+
+// validateAliasBindings 验证别名绑定列表
+func validateAliasBindings(bindings []AliasBinding) error {
+	if len(bindings) == 0 {
+		return fmt.Errorf("alias bindings cannot be empty")
+	}
+	for _, binding := range bindings {
+		if binding.CID == "" {
+			return ErrInvalidCID
+		}
+		if binding.Alias == "" {
+			return ErrInvalidAlias
+		}
+	}
+	return nil
 }
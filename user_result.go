@@ -0,0 +1,66 @@
+package getui
+
+import (
+	"strconv"
+	"time"
+)
+
+// 用户在线状态
+const (
+	UserStatusOnline  = "online"
+	UserStatusOffline = "offline"
+)
+
+// UserStatus 用户状态
+type UserStatus struct {
+	Status        string `json:"status"`          // online或offline
+	LastLoginTime string `json:"last_login_time"` // 最近登录时间（毫秒时间戳）
+}
+
+// Online 判断用户是否在线
+func (s *UserStatus) Online() bool {
+	return s.Status == UserStatusOnline
+}
+
+// LastLogin 获取最近登录时间，无法解析时返回零值
+func (s *UserStatus) LastLogin() time.Time {
+	ms, err := strconv.ParseInt(s.LastLoginTime, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// UserDetail 用户详情
+type UserDetail struct {
+	ClientAppID        string `json:"client_app_id"`
+	PackageName        string `json:"package_name"`
+	DeviceToken        string `json:"device_token"`
+	PhoneType          int    `json:"phone_type"` // 1：安卓，2：iOS
+	PhoneModel         string `json:"phone_model"`
+	NotificationSwitch bool   `json:"notification_switch"` // 通知开关是否打开
+	CreateTime         string `json:"create_time"`
+	LoginFreq          int    `json:"login_freq"` // 最近登录频次
+	Brand              string `json:"brand"`
+}
+
+// userDetailDTO 用户详情响应
+type userDetailDTO struct {
+	ValidCIDs   map[string]*UserDetail `json:"validCids"`
+	InvalidCIDs []string               `json:"invalidCids"`
+}
+
+// aliasDTO 别名响应
+type aliasDTO struct {
+	Alias string `json:"alias"`
+}
+
+// cidListDTO CID列表响应
+type cidListDTO struct {
+	CIDs []string `json:"cid"`
+}
+
+// userCountDTO 用户数响应
+type userCountDTO struct {
+	UserCount int64 `json:"user_count"`
+}
@@ -0,0 +1,71 @@
+package getui
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUserAPI_TypedResponses(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{
+		"cid_1":{"status":"online","last_login_time":"1700000000000"}
+	}}`)
+	statuses, err := client.UserAPI.QueryUserStatus([]string{"cid_1"})
+	assertNoError(t, err, "查询用户状态不应返回错误")
+	assertTrue(t, statuses["cid_1"].Online(), "用户应在线")
+	assertEqual(t, time.UnixMilli(1700000000000), statuses["cid_1"].LastLogin(), "应解析最近登录时间")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{"alias":"user_1"}}`)
+	alias, err := client.UserAPI.QueryAliasByCID("cid_1")
+	assertNoError(t, err, "查询别名不应返回错误")
+	assertEqual(t, "user_1", alias, "应返回别名")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{"cid":["cid_1","cid_2"]}}`)
+	cids, err := client.UserAPI.QueryCIDByAlias("user_1")
+	assertNoError(t, err, "查询CID不应返回错误")
+	assertEqual(t, 2, len(cids), "应返回全部CID")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{"cid_1":["vip","beijing"]}}`)
+	tags, err := client.UserAPI.GetUserTag("cid_1")
+	assertNoError(t, err, "查询标签不应返回错误")
+	assertEqual(t, 2, len(tags), "应返回CID的标签")
+
+	client = createResponseTestClient(t, `{"code":0,"msg":"success","data":{"user_count":42}}`)
+	count, err := client.UserAPI.GetUserCount()
+	assertNoError(t, err, "查询用户总量不应返回错误")
+	assertEqual(t, int64(42), count, "应返回用户总量")
+}
+
+func TestUserAPI_QueryUserDetail(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success","data":{
+		"validCids":{"cid_1":{"client_app_id":"app","phone_type":1,"notification_switch":true,"brand":"huawei"}},
+		"invalidCids":["cid_2"]
+	}}`)
+
+	detail, err := client.UserAPI.QueryUserDetail("cid_1")
+	assertNoError(t, err, "查询用户详情不应返回错误")
+	assertEqual(t, "huawei", detail.Brand, "应解析品牌")
+	assertTrue(t, detail.NotificationSwitch, "应解析通知开关")
+
+	_, err = client.UserAPI.QueryUserDetail("cid_2")
+	assertTrue(t, errors.Is(err, ErrUserNotFound), "无效CID应返回ErrUserNotFound")
+
+	client = createResponseTestClient(t, `{"code":10001,"msg":"token error"}`)
+	_, err = client.UserAPI.QueryUserDetail("cid_1")
+	assertErrorType(t, err, &APIError{}, "返回码非0时应返回APIError")
+}
+
+func TestUserAPI_AliasBindings(t *testing.T) {
+	client := createResponseTestClient(t, `{"code":0,"msg":"success"}`)
+
+	_, err := client.UserAPI.BindAliasBatch(nil)
+	assertError(t, err, "空列表应返回错误")
+	_, err = client.UserAPI.BindAliasBatch([]AliasBinding{{CID: "cid_1"}})
+	assertTrue(t, errors.Is(err, ErrInvalidAlias), "别名为空时应返回ErrInvalidAlias")
+	_, err = client.UserAPI.UnbindAliasBatch([]AliasBinding{{Alias: "user_1"}})
+	assertTrue(t, errors.Is(err, ErrInvalidCID), "CID为空时应返回ErrInvalidCID")
+
+	data, _ := json.Marshal(&aliasBatchDTO{DataList: []AliasBinding{{CID: "cid_1", Alias: "user_1"}}})
+	assertEqual(t, `{"data_list":[{"cid":"cid_1","alias":"user_1"}]}`, string(data), "请求体格式应与接口一致")
+}
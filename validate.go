@@ -0,0 +1,130 @@
+package getui
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Validate 验证配置，返回的ConfigErrors包含发现的全部问题。
+// 必填项缺失时可通过errors.Is匹配ErrAppIDRequired等错误
+func (c *Config) Validate() error {
+	var errs ConfigErrors
+	add := func(field, message string, sentinel error) {
+		errs = append(errs, &ConfigError{Field: field, Message: message, Err: sentinel})
+	}
+
+	// 必填项
+	if c.AppID == "" {
+		add("app_id", ErrAppIDRequired.Error(), ErrAppIDRequired)
+	}
+	if c.AppKey == "" {
+		add("app_key", ErrAppKeyRequired.Error(), ErrAppKeyRequired)
+	}
+	if c.MasterSecret == "" && c.SecretProvider == nil {
+		add("master_secret", ErrMasterSecretRequired.Error(), ErrMasterSecretRequired)
+	}
+
+	// 域名
+	if c.Domain == "" && len(c.Domains) == 0 {
+		add("domain", ErrDomainRequired.Error(), ErrDomainRequired)
+	}
+	if c.Domain != "" {
+		if msg := validateDomain(c.Domain); msg != "" {
+			add("domain", msg, nil)
+		}
+	}
+	for i, domain := range c.Domains {
+		if msg := validateDomain(domain); msg != "" {
+			add(fmt.Sprintf("domains[%d]", i), msg, nil)
+		}
+	}
+
+	// 超时
+	nonNegative := func(field string, value int) {
+		if value < 0 {
+			add(field, fmt.Sprintf("must not be negative, got %d", value), nil)
+		}
+	}
+	nonNegative("socket_timeout", c.SocketTimeout)
+	nonNegative("connect_timeout", c.ConnectTimeout)
+	nonNegative("connection_request_timeout", c.ConnectionRequestTimeout)
+	nonNegative("http_check_timeout", c.HTTPCheckTimeout)
+	nonNegative("max_http_try_time", c.MaxHTTPTryTime)
+	nonNegative("max_response_body_size", c.MaxResponseBodySize)
+	uris := make([]string, 0, len(c.URIToSocketTimeoutMap))
+	for uri := range c.URIToSocketTimeoutMap {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		nonNegative(fmt.Sprintf("uri_to_socket_timeout_map[%s]", uri), c.URIToSocketTimeoutMap[uri])
+	}
+
+	nonNegativeDuration := func(field string, value time.Duration) {
+		if value < 0 {
+			add(field, fmt.Sprintf("must not be negative, got %s", value), nil)
+		}
+	}
+	nonNegativeDuration("token_expire_margin", c.TokenExpireMargin)
+	nonNegativeDuration("token_auto_refresh_ahead", c.TokenAutoRefreshAhead)
+	nonNegativeDuration("analyse_stable_domain_interval", c.AnalyseStableDomainInterval)
+	nonNegativeDuration("check_max_failed_num_interval", c.CheckMaxFailedNumInterval)
+	nonNegativeDuration("check_health_interval", c.CheckHealthInterval)
+
+	// 故障切换阈值
+	nonNegative("max_failed_num", c.MaxFailedNum)
+	nonNegative("continuous_failed_num", c.ContinuousFailedNum)
+	if c.MaxFailedNum > 0 && c.ContinuousFailedNum > c.MaxFailedNum {
+		add("continuous_failed_num", fmt.Sprintf("must not exceed max_failed_num (%d), got %d", c.MaxFailedNum, c.ContinuousFailedNum), nil)
+	}
+
+	// 重试策略
+	if p := c.RetryPolicy; p != nil {
+		nonNegative("retry_policy.max_attempts", p.MaxAttempts)
+		nonNegativeDuration("retry_policy.initial_backoff", p.InitialBackoff)
+		nonNegativeDuration("retry_policy.max_backoff", p.MaxBackoff)
+		if p.MaxBackoff > 0 && p.MaxBackoff < p.InitialBackoff {
+			add("retry_policy.max_backoff", fmt.Sprintf("must not be less than initial_backoff (%s), got %s", p.InitialBackoff, p.MaxBackoff), nil)
+		}
+		if p.Multiplier < 0 {
+			add("retry_policy.multiplier", fmt.Sprintf("must not be negative, got %g", p.Multiplier), nil)
+		}
+		if p.Jitter < 0 || p.Jitter > 1 {
+			add("retry_policy.jitter", fmt.Sprintf("must be between 0 and 1, got %g", p.Jitter), nil)
+		}
+	}
+
+	// 代理
+	if p := c.ProxyConfig; p != nil {
+		var configErr *ConfigError
+		if _, err := p.URL(); errors.As(err, &configErr) {
+			errs = append(errs, configErr)
+		}
+		if p.Password != "" && p.Username == "" {
+			add("proxy_config.username", "username is required when password is set", nil)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateDomain 校验域名格式，合法时返回空字符串
+func validateDomain(domain string) string {
+	u, err := url.Parse(domain)
+	if err != nil {
+		return fmt.Sprintf("malformed url %q: %v", domain, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Sprintf("url %q must use http or https", domain)
+	}
+	if u.Host == "" {
+		return fmt.Sprintf("url %q has no host", domain)
+	}
+	return ""
+}